@@ -0,0 +1,97 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"time"
+	"unsafe"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// BenchmarkResult contains the speed of a single device for a hash mode
+type BenchmarkResult struct {
+	HashMode      int
+	HashName      string
+	DeviceID      int
+	HashesPerSec  float64
+	KernelAccel   int
+	KernelLoops   int
+	KernelThreads int
+	Runtime       time.Duration
+}
+
+// Benchmark runs hashcat's --benchmark for each of the hash modes and returns the speed of every device.
+// If modes is empty, hashcat's default set of hash modes is benchmarked. opts can be used to select devices,
+// the workload profile, optimized kernels, etc. Any hashes or dictionaries in opts are ignored
+func (hc *Hashcat) Benchmark(modes []int, opts hcargp.HashcatSessionOptions) ([]BenchmarkResult, error) {
+	opts.InputFile = ""
+	opts.DictionaryMaskDirectoryInput = nil
+
+	if len(modes) == 0 {
+		return hc.benchmark(opts)
+	}
+
+	var results []BenchmarkResult
+	for _, mode := range modes {
+		opts.HashType = hcargp.GetIntPtr(mode)
+
+		res, err := hc.benchmark(opts)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, res...)
+	}
+	return results, nil
+}
+
+func (hc *Hashcat) benchmark(opts hcargp.HashcatSessionOptions) ([]BenchmarkResult, error) {
+	args, err := opts.MarshalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BenchmarkResult
+
+	job := newJobState()
+	job.hook = func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) {
+		// hashcat finishes the cracker once for every hash mode it benchmarks
+		if id != C.EVENT_CRACKER_FINISHED || hcCtx == nil {
+			return
+		}
+
+		hashMode := int(hcCtx.hashconfig.hash_mode)
+		readStatus(hcCtx, func(hcStatus *C.hashcat_status_t) {
+			results = append(results, benchmarkResultsFromC(hashMode, hcStatus)...)
+		})
+	}
+
+	if _, err := hc.run(append(args, "--benchmark"), job); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func benchmarkResultsFromC(hashMode int, hcStatus *C.hashcat_status_t) []BenchmarkResult {
+	var results []BenchmarkResult
+
+	for i := 0; i < int(hcStatus.device_info_cnt); i++ {
+		deviceInfo := hcStatus.device_info_buf[i]
+		if deviceInfo.skipped_dev {
+			continue
+		}
+
+		results = append(results, BenchmarkResult{
+			HashMode:      hashMode,
+			HashName:      C.GoString(hcStatus.hash_name),
+			DeviceID:      i + 1,
+			HashesPerSec:  float64(deviceInfo.hashes_msec_dev_benchmark) * 1000,
+			KernelAccel:   int(deviceInfo.kernel_accel_dev),
+			KernelLoops:   int(deviceInfo.kernel_loops_dev),
+			KernelThreads: int(deviceInfo.kernel_threads_dev),
+			Runtime:       time.Duration(float64(deviceInfo.runtime_msec_dev) * float64(time.Millisecond)),
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,101 @@
+package gocat
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// ErrCandidatesUnsupported is raised when Job.Candidates or GenerateCandidates are used on a platform where
+// hashcat's stdin or stdout can't be redirected
+var ErrCandidatesUnsupported = errors.New("gocat: candidates are not supported on this platform")
+
+// GenerateCandidates streams the password candidates of the attack described by opts into w using hashcat's --stdout mode.
+// Candidates are written one per line as hashcat produces them. opts.Skip and opts.Limit can be used to only generate
+// part of the keyspace. opts.InputFile is ignored and opts are checked with ValidateCandidates before hashcat is started.
+// NOTE: this is only supported on systems that provide /dev/fd (linux, darwin), ErrCandidatesUnsupported is returned elsewhere
+func (hc *Hashcat) GenerateCandidates(opts hcargp.HashcatSessionOptions, w io.Writer) error {
+	opts.InputFile = ""
+	if err := opts.ValidateCandidates(); err != nil {
+		return err
+	}
+	return hc.generateCandidates(opts, w)
+}
+
+// candidateChanReader turns a channel of candidates into newline separated input for hashcat
+type candidateChanReader struct {
+	ch  <-chan string
+	buf string
+}
+
+// CandidatesFromChan returns an io.Reader that can be used as Job.Candidates. Each string received from ch is a candidate
+// and the reader hits EOF once ch is closed. Candidates must not contain a newline.
+// Close ch when you're done sending candidates, otherwise the goroutine feeding hashcat will leak
+func CandidatesFromChan(ch <-chan string) io.Reader {
+	return &candidateChanReader{ch: ch}
+}
+
+func (r *candidateChanReader) Read(p []byte) (int, error) {
+	return r.readUntil(p, nil)
+}
+
+// readUntil is Read but it gives up waiting for a candidate and returns io.EOF once stop is closed
+func (r *candidateChanReader) readUntil(p []byte, stop <-chan struct{}) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for r.buf == "" {
+		select {
+		case candidate, ok := <-r.ch:
+			if !ok {
+				return 0, io.EOF
+			}
+			r.buf = strings.TrimRight(candidate, "\r\n") + "\n"
+		case <-stop:
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// copyCandidates copies r into w until r is exhausted, writing to w fails or stop is closed.
+// A read of r is never started once stop is closed, a reader from CandidatesFromChan also stops waiting for candidates
+func copyCandidates(w io.Writer, r io.Reader, stop <-chan struct{}) error {
+	chanReader, _ := r.(*candidateChanReader)
+	buf := make([]byte, 32*1024)
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		var n int
+		var err error
+		if chanReader != nil {
+			n, err = chanReader.readUntil(buf, stop)
+		} else {
+			n, err = r.Read(buf)
+		}
+
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows && !(linux && cgo) && !(darwin && cgo)
+// +build !windows
+// +build !linux !cgo
+// +build !darwin !cgo
+
+package gocat
+
+import "io"
+
+// redirectStdin is only implemented on linux and darwin
+func redirectStdin(r io.Reader) (restore func(), err error) {
+	return nil, ErrCandidatesUnsupported
+}
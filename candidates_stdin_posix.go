@@ -0,0 +1,97 @@
+//go:build (linux && cgo) || (darwin && cgo)
+// +build linux,cgo darwin,cgo
+
+package gocat
+
+/*
+#include <stdio.h>
+#include <unistd.h>
+#ifndef __APPLE__
+#include <stdio_ext.h>
+#endif
+
+// reset_stdin drops anything left in stdin's buffer and clears its EOF/error flags so the next
+// session reads from whatever is on fd 0 now
+static void reset_stdin(void)
+{
+#ifdef __APPLE__
+  fpurge(stdin);
+#else
+  __fpurge(stdin);
+#endif
+  clearerr(stdin);
+}
+
+static int redirect_stdin(int fd)
+{
+  int saved = dup(STDIN_FILENO);
+  if (saved == -1) return -1;
+
+  if (dup2(fd, STDIN_FILENO) == -1)
+  {
+    close(saved);
+    return -1;
+  }
+
+  reset_stdin();
+  return saved;
+}
+
+static int restore_stdin(int saved)
+{
+  int rc = dup2(saved, STDIN_FILENO);
+  close(saved);
+  reset_stdin();
+  return rc;
+}
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// stdinMu serializes sessions reading candidates from stdin as fd 0 is shared by the whole process
+var stdinMu sync.Mutex
+
+var errRedirectStdin = errors.New("failed to redirect stdin")
+
+// redirectStdin replaces the process' stdin with a pipe that's fed from r until restore is called.
+// This is how hashcat's stdin attack modes are driven from go. Once restore is called no more reads of r are started,
+// a read that's already blocked in r (other than a reader from CandidatesFromChan) returns whenever r does and its data is discarded
+// NOTE: this only works on posix systems (linux, darwin)
+func redirectStdin(r io.Reader) (restore func(), err error) {
+	stdinMu.Lock()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		stdinMu.Unlock()
+		return nil, err
+	}
+
+	saved := C.redirect_stdin(C.int(pr.Fd()))
+	// fd 0 now holds its own reference to the read end of the pipe
+	pr.Close()
+	if saved == -1 {
+		pw.Close()
+		stdinMu.Unlock()
+		return nil, errRedirectStdin
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		// the copy owns the write end of the pipe and is the only one closing it
+		defer pw.Close()
+		// this fails with EPIPE if hashcat stops reading before r is exhausted
+		copyCandidates(pw, r, stop)
+	}()
+
+	return func() {
+		close(stop)
+		// closing the read end of the pipe unblocks the copy above if it's still writing
+		C.restore_stdin(saved)
+		stdinMu.Unlock()
+	}, nil
+}
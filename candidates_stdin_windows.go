@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package gocat
+
+import "io"
+
+// redirectStdin is not supported on windows
+func redirectStdin(r io.Reader) (restore func(), err error) {
+	return nil, ErrCandidatesUnsupported
+}
@@ -0,0 +1,16 @@
+//go:build !(linux && cgo) && !(darwin && cgo)
+// +build !linux !cgo
+// +build !darwin !cgo
+
+package gocat
+
+import (
+	"io"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// generateCandidates is only implemented on linux and darwin, hashcat needs a path to the pipe in /dev/fd
+func (hc *Hashcat) generateCandidates(opts hcargp.HashcatSessionOptions, w io.Writer) error {
+	return ErrCandidatesUnsupported
+}
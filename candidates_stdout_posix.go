@@ -0,0 +1,50 @@
+//go:build (linux && cgo) || (darwin && cgo)
+// +build linux,cgo darwin,cgo
+
+package gocat
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// generateCandidates runs hashcat with --stdout and copies the candidates into w
+func (hc *Hashcat) generateCandidates(opts hcargp.HashcatSessionOptions, w io.Writer) error {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer pr.Close()
+
+	// hashcat reopens the outfile for every chunk of candidates, so we hand it a path to our end of the pipe
+	// that stays valid until we close it once the session has finished
+	opts.OutfilePath = hcargp.GetStringPtr(fmt.Sprintf("/dev/fd/%d", pw.Fd()))
+
+	args, err := opts.MarshalArgs()
+	if err != nil {
+		pw.Close()
+		return err
+	}
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, pr)
+		if err != nil {
+			// keep draining the pipe so hashcat doesn't block forever on a writer that went away
+			io.Copy(io.Discard, pr)
+		}
+		copied <- err
+	}()
+
+	_, runErr := hc.run(append(args, "--stdout"), newJobState())
+	pw.Close()
+
+	copyErr := <-copied
+	if runErr != nil {
+		return runErr
+	}
+	return copyErr
+}
@@ -0,0 +1,101 @@
+package gocat
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidatesFromChan(t *testing.T) {
+	ch := make(chan string, 4)
+	ch <- "hello"
+	ch <- ""
+	ch <- "world\n"
+	ch <- "hashcat!"
+	close(ch)
+
+	b, err := io.ReadAll(CandidatesFromChan(ch))
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n\nworld\nhashcat!\n", string(b))
+}
+
+// countingReader counts how many times Read is called
+type countingReader struct {
+	io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func TestCopyCandidates(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, copyCandidates(&buf, strings.NewReader("hello\nworld\n"), nil))
+	assert.Equal(t, "hello\nworld\n", buf.String())
+
+	// nothing is read once the session has stopped
+	stop := make(chan struct{})
+	close(stop)
+
+	r := &countingReader{Reader: strings.NewReader("hello\n")}
+	buf.Reset()
+	require.NoError(t, copyCandidates(&buf, r, stop))
+	assert.Zero(t, r.reads)
+	assert.Empty(t, buf.String())
+}
+
+func TestCopyCandidatesStopsWaitingOnChan(t *testing.T) {
+	ch := make(chan string, 1)
+	ch <- "hello"
+	// ch is never closed
+
+	stop := make(chan struct{})
+	pr, pw := io.Pipe()
+	copied := make(chan error, 1)
+	go func() {
+		copied <- copyCandidates(pw, CandidatesFromChan(ch), stop)
+	}()
+
+	line := make([]byte, 6)
+	_, err := io.ReadFull(pr, line)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(line))
+
+	close(stop)
+	select {
+	case err := <-copied:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("copyCandidates kept waiting for candidates after stop was closed")
+	}
+}
+
+func TestJobArgsWithCandidates(t *testing.T) {
+	job := Job{
+		Options: &hcargp.HashcatSessionOptions{
+			AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:                     hcargp.GetIntPtr(0),
+			InputFile:                    "./testdata/two_md5.hashes",
+			DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+		},
+		Candidates: CandidatesFromChan(nil),
+	}
+
+	args, err := job.args()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--hash-type=0", "--attack-mode=0", "./testdata/two_md5.hashes"}, args)
+	// the caller's options are left untouched
+	assert.NotNil(t, job.Options.DictionaryMaskDirectoryInput)
+
+	job.Options.AttackMode = hcargp.GetAttackModePtr(hcargp.AttackBruteForce)
+	_, err = job.args()
+	assert.Equal(t, ErrCandidatesAttackMode, err)
+}
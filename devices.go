@@ -0,0 +1,123 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import "unsafe"
+
+// Device describes a backend device found by hashcat
+type Device struct {
+	// ID is the device ID as used by --backend-devices
+	ID int
+	// Platform is the backend used to access the device: CUDA, HIP, Metal or OpenCL
+	Platform string
+	// Type is CPU, GPU or Accelerator
+	Type string
+	Name string
+	// Vendor is the vendor reported by OpenCL, other backends report the vendor hashcat detected (AMD, Apple, Intel, NVIDIA, etc)
+	Vendor string
+	// GlobalMemory is the size of the device's global memory in bytes
+	GlobalMemory uint64
+	ComputeUnits int
+	// ClockMHz is the maximum clock frequency of the device
+	ClockMHz int
+	// Skipped is true if hashcat won't use this device (usually due to --backend-devices, --opencl-device-types or an unsupported driver)
+	Skipped bool
+}
+
+// ListDevices returns the OpenCL, CUDA, HIP and Metal devices available to hashcat. This is an implementation of --backend-info
+func ListDevices(options Options) ([]Device, error) {
+	hc, err := New(options, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer hc.Free()
+
+	var devices []Device
+
+	job := newJobState()
+	job.initOnly = func(hcCtx *C.hashcat_ctx_t) {
+		devices = devicesFromC(hcCtx.backend_ctx)
+	}
+
+	if _, err := hc.run([]string{"--backend-info"}, job); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func devicesFromC(backendCtx *C.backend_ctx_t) []Device {
+	if backendCtx == nil || backendCtx.devices_param == nil {
+		return nil
+	}
+
+	params := unsafe.Slice(backendCtx.devices_param, int(backendCtx.backend_devices_cnt))
+	devices := make([]Device, 0, len(params))
+
+	for _, param := range params {
+		device := Device{
+			ID:           int(param.device_id) + 1,
+			Name:         C.GoString(param.device_name),
+			GlobalMemory: uint64(param.device_global_mem),
+			ComputeUnits: int(param.device_processors),
+			ClockMHz:     int(param.device_maxclock_frequency),
+			Skipped:      bool(param.skipped),
+		}
+
+		switch {
+		case bool(param.is_cuda):
+			device.Platform = "CUDA"
+		case bool(param.is_hip):
+			device.Platform = "HIP"
+		case bool(param.is_metal):
+			device.Platform = "Metal"
+		case bool(param.is_opencl):
+			device.Platform = "OpenCL"
+		}
+
+		// hashcat fills in the OpenCL type and vendor ID for every backend
+		device.Type = openCLDeviceType(param.opencl_device_type)
+		if param.opencl_device_vendor != nil {
+			device.Vendor = C.GoString(param.opencl_device_vendor)
+		} else {
+			device.Vendor = deviceVendor(param.opencl_device_vendor_id)
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices
+}
+
+func openCLDeviceType(t C.cl_device_type) string {
+	switch {
+	case t&C.CL_DEVICE_TYPE_GPU != 0:
+		return "GPU"
+	case t&C.CL_DEVICE_TYPE_CPU != 0:
+		return "CPU"
+	case t&C.CL_DEVICE_TYPE_ACCELERATOR != 0:
+		return "Accelerator"
+	default:
+		return "Unknown"
+	}
+}
+
+func deviceVendor(id C.u32) string {
+	switch {
+	case id&(C.VENDOR_ID_AMD|C.VENDOR_ID_AMD_USE_INTEL|C.VENDOR_ID_AMD_USE_HIP) != 0:
+		return "AMD"
+	case id&C.VENDOR_ID_APPLE != 0:
+		return "Apple"
+	case id&(C.VENDOR_ID_INTEL_BEIGNET|C.VENDOR_ID_INTEL_SDK) != 0:
+		return "Intel"
+	case id&C.VENDOR_ID_MESA != 0:
+		return "Mesa"
+	case id&C.VENDOR_ID_NV != 0:
+		return "NVIDIA"
+	case id&C.VENDOR_ID_POCL != 0:
+		return "POCL"
+	case id&C.VENDOR_ID_MICROSOFT != 0:
+		return "Microsoft"
+	default:
+		return "Unknown"
+	}
+}
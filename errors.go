@@ -0,0 +1,188 @@
+package gocat
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoDevices is raised when hashcat was unable to find (or use) any backend device
+	ErrNoDevices = errors.New("no devices found")
+	// ErrNoHashesLoaded is raised when none of the hashes passed into hashcat could be loaded.
+	// The reason for each rejected hash is available as a HashParseError
+	ErrNoHashesLoaded = errors.New("no hashes loaded")
+	// ErrTokenLength is the reason of a HashParseError when a hash has the wrong length for the hash type
+	ErrTokenLength = errors.New("token length exception")
+	// ErrSeparatorUnmatched is the reason of a HashParseError when a hash is missing the separator required by the hash type
+	ErrSeparatorUnmatched = errors.New("separator unmatched")
+	// ErrFileNotFound is raised when a file passed into hashcat does not exist. It is the same as fs.ErrNotExist
+	ErrFileNotFound = fs.ErrNotExist
+	// ErrKernelBuild is raised when hashcat was unable to compile a kernel for a device
+	ErrKernelBuild = errors.New("kernel build failed")
+	// ErrInsufficientDeviceMemory is raised when a device does not have enough memory for the attack
+	ErrInsufficientDeviceMemory = errors.New("insufficient device memory")
+	// ErrSelfTestFailed is raised when a device fails hashcat's kernel self-test
+	ErrSelfTestFailed = errors.New("self-test failed")
+)
+
+// HashcatError is returned whenever libhashcat reports a failure. If the message from hashcat
+// was recognized, Err is set to one of the sentinel errors in this package (or an error wrapping one)
+// so it can be matched with errors.Is and errors.As
+type HashcatError struct {
+	Message string
+	Err     error
+}
+
+func (e *HashcatError) Error() string {
+	return "gocat: " + e.Message
+}
+
+func (e *HashcatError) Unwrap() error {
+	return e.Err
+}
+
+// HashParseError describes a hash that hashcat was unable to parse
+type HashParseError struct {
+	// Hash is the rejected hash (or the line of the hashfile)
+	Hash string
+	// File is the hashfile the hash was read from. It's empty if a single hash was passed in
+	File string
+	// Line is the line number of the hash within File
+	Line int
+	// Reason is the parser error reported by hashcat
+	Reason string
+	// Err is ErrTokenLength or ErrSeparatorUnmatched for those failures and nil otherwise
+	Err error
+}
+
+func (e *HashParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("hashfile '%s' on line %d (%s): %s", e.File, e.Line, e.Hash, e.Reason)
+	}
+	return fmt.Sprintf("hash '%s': %s", e.Hash, e.Reason)
+}
+
+func (e *HashParseError) Unwrap() error {
+	return e.Err
+}
+
+// DeviceError is a failure reported by hashcat for a specific backend device
+type DeviceError struct {
+	DeviceID int
+	Message  string
+	Err      error
+}
+
+func (e *DeviceError) Error() string {
+	return fmt.Sprintf("device #%d: %s", e.DeviceID, e.Message)
+}
+
+func (e *DeviceError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable returns true if err was caused by a device problem that might go away on its own
+// (another workload holding device memory, a flaky driver, etc) and the session is worth retrying.
+// Errors caused by the session's input (bad hashes, missing files) are never retryable
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrNoDevices) ||
+		errors.Is(err, ErrKernelBuild) ||
+		errors.Is(err, ErrInsufficientDeviceMemory) ||
+		errors.Is(err, ErrSelfTestFailed)
+}
+
+var (
+	rxpHashfileParse = regexp.MustCompile(`^Hashfile '(.*)' on line (\d+) \((.*)\): (.+?)\.?$`)
+	rxpHashParse     = regexp.MustCompile(`^Hash '(.*)': (.+?)\.?$`)
+	rxpDevice        = regexp.MustCompile(`^\* Device #(\d+): (.+)$`)
+	rxpNoSuchFile    = regexp.MustCompile(`^(.+): No such file or directory\.?$`)
+
+	parserReasons = map[string]error{
+		"token length exception": ErrTokenLength,
+		"separator unmatched":    ErrSeparatorUnmatched,
+	}
+)
+
+// newHashcatError converts a log message from hashcat into a HashcatError
+func newHashcatError(msg string) *HashcatError {
+	msg = strings.TrimSpace(msg)
+	return &HashcatError{
+		Message: msg,
+		Err:     parseHashcatMessage(msg),
+	}
+}
+
+// parseHashcatMessage returns the error described by msg or nil if it isn't recognized
+func parseHashcatMessage(msg string) error {
+	if pe := parseHashParseError(msg); pe != nil {
+		return pe
+	}
+
+	if matches := rxpNoSuchFile.FindStringSubmatch(msg); matches != nil {
+		return &os.PathError{Op: "open", Path: matches[1], Err: ErrFileNotFound}
+	}
+
+	if matches := rxpDevice.FindStringSubmatch(msg); matches != nil {
+		if err := classifyMessage(matches[2]); err != nil {
+			id, _ := strconv.Atoi(matches[1])
+			return &DeviceError{
+				DeviceID: id,
+				Message:  matches[2],
+				Err:      err,
+			}
+		}
+	}
+
+	return classifyMessage(msg)
+}
+
+func parseHashParseError(msg string) *HashParseError {
+	if matches := rxpHashfileParse.FindStringSubmatch(msg); matches != nil {
+		line, _ := strconv.Atoi(matches[2])
+		return &HashParseError{
+			File:   matches[1],
+			Line:   line,
+			Hash:   matches[3],
+			Reason: matches[4],
+			Err:    parserReasons[strings.ToLower(matches[4])],
+		}
+	}
+
+	if matches := rxpHashParse.FindStringSubmatch(msg); matches != nil {
+		return &HashParseError{
+			Hash:   matches[1],
+			Reason: matches[2],
+			Err:    parserReasons[strings.ToLower(matches[2])],
+		}
+	}
+
+	return nil
+}
+
+func classifyMessage(msg string) error {
+	lmsg := strings.ToLower(msg)
+
+	switch {
+	case strings.Contains(lmsg, "no hashes loaded"):
+		return ErrNoHashesLoaded
+	case strings.Contains(lmsg, "no devices found"),
+		strings.Contains(lmsg, "compatible platform found"):
+		return ErrNoDevices
+	case strings.Contains(lmsg, "build failed"):
+		return ErrKernelBuild
+	case strings.Contains(lmsg, "not enough allocatable device memory"),
+		strings.Contains(lmsg, "out_of_memory"),
+		strings.Contains(lmsg, "out of memory"),
+		strings.Contains(lmsg, "mem_object_allocation_failure"):
+		return ErrInsufficientDeviceMemory
+	case strings.Contains(lmsg, "self-test failed"):
+		return ErrSelfTestFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package gocat
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHashcatMessage(t *testing.T) {
+	for _, test := range []struct {
+		msg       string
+		expected  error
+		retryable bool
+	}{
+		{msg: "No devices found/left.", expected: ErrNoDevices, retryable: true},
+		{msg: "ATTENTION! No OpenCL, Metal, HIP or CUDA compatible platform found.", expected: ErrNoDevices, retryable: true},
+		{msg: "No hashes loaded.", expected: ErrNoHashesLoaded},
+		{msg: "Hash 'deadbeef': Token length exception", expected: ErrTokenLength},
+		{msg: "Hashfile 'hashes.txt' on line 3 (bob): Separator unmatched", expected: ErrSeparatorUnmatched},
+		{msg: "./testdata/nope.dict: No such file or directory", expected: fs.ErrNotExist},
+		{msg: "* Device #1: Kernel /usr/local/share/hashcat/OpenCL/m00000_a0-optimized.cl build failed.", expected: ErrKernelBuild, retryable: true},
+		{msg: "* Device #2: Not enough allocatable device memory for this attack.", expected: ErrInsufficientDeviceMemory, retryable: true},
+		{msg: "* Device #1: ATTENTION! OpenCL kernel self-test failed.", expected: ErrSelfTestFailed, retryable: true},
+		{msg: "Something else went wrong", expected: nil},
+	} {
+		err := newHashcatError(test.msg)
+		require.Equal(t, "gocat: "+test.msg, err.Error())
+
+		if test.expected == nil {
+			require.Nil(t, err.Err, test.msg)
+		} else {
+			require.True(t, errors.Is(err, test.expected), test.msg)
+		}
+		require.Equal(t, test.retryable, IsRetryable(err), test.msg)
+	}
+}
+
+func TestParseHashcatMessageDetails(t *testing.T) {
+	var pe *HashParseError
+	err := newHashcatError("Hashfile 'hashes.txt' on line 3 (bob): Token length exception")
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, "hashes.txt", pe.File)
+	require.Equal(t, 3, pe.Line)
+	require.Equal(t, "bob", pe.Hash)
+	require.Equal(t, "Token length exception", pe.Reason)
+
+	var de *DeviceError
+	err = newHashcatError("* Device #2: Not enough allocatable device memory for this attack.")
+	require.True(t, errors.As(err, &de))
+	require.Equal(t, 2, de.DeviceID)
+
+	var pathErr *os.PathError
+	err = newHashcatError("./testdata/nope.dict: No such file or directory")
+	require.True(t, errors.As(err, &pathErr))
+	require.Equal(t, "./testdata/nope.dict", pathErr.Path)
+}
+
+func TestJobStateSessionErrorIncludesRejectedHashes(t *testing.T) {
+	job := newJobState()
+	job.addParseError(parseHashParseError("Hashfile 'hashes.txt' on line 1 (nope): Token length exception"))
+	job.addParseError(parseHashParseError("Hashfile 'hashes.txt' on line 2 (nope:): Separator unmatched"))
+
+	err := job.sessionError(newHashcatError("No hashes loaded."))
+	require.True(t, errors.Is(err, ErrNoHashesLoaded))
+	require.True(t, errors.Is(err, ErrTokenLength))
+	require.True(t, errors.Is(err, ErrSeparatorUnmatched))
+
+	var pe *HashParseError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, 1, pe.Line)
+}
@@ -0,0 +1,231 @@
+package gocat
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultEventBufferSize is the size of the channel returned by Hashcat.Events when Options.EventBufferSize is not set
+const DefaultEventBufferSize = 256
+
+// EventOverflowPolicy decides what happens to new events once the Events channel is full
+type EventOverflowPolicy int
+
+const (
+	// EventOverflowUnbounded keeps every event and delivers them in order once the consumer catches up.
+	// Events waiting for delivery are queued in memory without a limit so hashcat itself is never blocked,
+	// a consumer that stops reading makes the queue (and memory usage) grow for as long as the session runs
+	EventOverflowUnbounded EventOverflowPolicy = iota
+	// EventOverflowDropOldest discards the oldest undelivered event to make room for a new one
+	EventOverflowDropOldest
+	// EventOverflowCoalesce replaces the most recent undelivered event of the same payload type with the new one.
+	// CrackedPayload events are never coalesced or dropped
+	EventOverflowCoalesce
+)
+
+func (p EventOverflowPolicy) String() string {
+	switch p {
+	case EventOverflowUnbounded:
+		return "Unbounded"
+	case EventOverflowDropOldest:
+		return "DropOldest"
+	case EventOverflowCoalesce:
+		return "Coalesce"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is a single event from hashcat delivered through Hashcat.Events
+type Event struct {
+	// ID is the hashcat event (EVENT_*) that produced this event
+	ID   uint32
+	Time time.Time
+	// Payload is one of the payloads that are also passed into EventCallback (LogPayload, ActionPayload, CrackedPayload, etc)
+	Payload interface{}
+}
+
+// Events returns a channel that receives every event also passed into the EventCallback. The channel is buffered
+// with Options.EventBufferSize and Options.EventOverflowPolicy decides what happens once it is full.
+// Events are delivered from a separate goroutine so a slow consumer never blocks hashcat.
+// The channel is closed when Free is called, calling Events after Free returns a closed channel.
+func (hc *Hashcat) Events() <-chan Event {
+	hc.eventsOnce.Do(func() {
+		hc.events.Store(newEventStream(hc.opts.EventBufferSize, hc.opts.EventOverflowPolicy))
+	})
+
+	if s := hc.events.Load(); s != nil {
+		return s.out
+	}
+
+	closed := make(chan Event)
+	close(closed)
+	return closed
+}
+
+// closeEvents closes the Events channel and prevents a new one from being created
+func (hc *Hashcat) closeEvents() {
+	hc.eventsOnce.Do(func() {})
+
+	if s := hc.events.Load(); s != nil {
+		s.close()
+	}
+}
+
+// DroppedEvents returns the number of events discarded because the Events channel was full
+func (hc *Hashcat) DroppedEvents() uint64 {
+	if s := hc.events.Load(); s != nil {
+		return s.droppedCount()
+	}
+	return 0
+}
+
+// eventStream queues events from hashcat's threads and pumps them into out
+type eventStream struct {
+	mu       sync.Mutex
+	pending  []Event
+	inflight bool
+	shutdown bool
+	dropped  uint64
+	limit    int
+	policy   EventOverflowPolicy
+
+	out    chan Event
+	notify chan struct{}
+	closed chan struct{}
+	done   chan struct{}
+}
+
+func newEventStream(size int, policy EventOverflowPolicy) *eventStream {
+	if size <= 0 {
+		size = DefaultEventBufferSize
+	}
+
+	s := &eventStream{
+		limit:  size,
+		policy: policy,
+		out:    make(chan Event, size),
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.pump()
+	return s
+}
+
+// push queues e for delivery. It never blocks
+func (s *eventStream) push(e Event) {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return
+	}
+
+	// Skip the queue whenever nothing is waiting ahead of this event
+	if len(s.pending) == 0 && !s.inflight {
+		select {
+		case s.out <- e:
+			s.mu.Unlock()
+			return
+		default:
+		}
+	}
+
+	if len(s.pending) >= s.limit {
+		s.makeRoom(e)
+	}
+	s.pending = append(s.pending, e)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// makeRoom applies the overflow policy to the pending queue. s.mu must be held
+func (s *eventStream) makeRoom(e Event) {
+	switch s.policy {
+	case EventOverflowDropOldest:
+		s.remove(0)
+	case EventOverflowCoalesce:
+		if _, ok := e.Payload.(CrackedPayload); !ok {
+			for i := len(s.pending) - 1; i >= 0; i-- {
+				if reflect.TypeOf(s.pending[i].Payload) == reflect.TypeOf(e.Payload) {
+					s.remove(i)
+					return
+				}
+			}
+		}
+
+		for i := range s.pending {
+			if _, ok := s.pending[i].Payload.(CrackedPayload); !ok {
+				s.remove(i)
+				return
+			}
+		}
+	}
+}
+
+func (s *eventStream) remove(i int) {
+	s.pending = append(s.pending[:i], s.pending[i+1:]...)
+	s.dropped++
+}
+
+func (s *eventStream) pump() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.notify:
+		case <-s.closed:
+			return
+		}
+
+		for {
+			s.mu.Lock()
+			if len(s.pending) == 0 {
+				s.mu.Unlock()
+				break
+			}
+
+			e := s.pending[0]
+			s.pending[0] = Event{}
+			s.pending = s.pending[1:]
+			s.inflight = true
+			s.mu.Unlock()
+
+			select {
+			case s.out <- e:
+			case <-s.closed:
+				return
+			}
+
+			s.mu.Lock()
+			s.inflight = false
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *eventStream) droppedCount() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// close stops the pump and closes out. Undelivered events are discarded
+func (s *eventStream) close() {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return
+	}
+	s.shutdown = true
+	s.mu.Unlock()
+
+	close(s.closed)
+	<-s.done
+	close(s.out)
+}
@@ -0,0 +1,113 @@
+package gocat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func drainEvents(t *testing.T, ch <-chan Event, n int) []Event {
+	events := make([]Event, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-ch:
+			events = append(events, e)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+	return events
+}
+
+func TestEventStreamUnboundedKeepsOrder(t *testing.T) {
+	s := newEventStream(2, EventOverflowUnbounded)
+	defer s.close()
+
+	for i := 0; i < 10; i++ {
+		s.push(Event{ID: uint32(i), Payload: LogPayload{}})
+	}
+
+	events := drainEvents(t, s.out, 10)
+	for i, e := range events {
+		require.Equal(t, uint32(i), e.ID)
+	}
+	require.Equal(t, uint64(0), s.droppedCount())
+}
+
+// stalledEventStream returns an eventStream without a pump whose channel is already full so that
+// every new event lands in the pending queue
+func stalledEventStream(size int, policy EventOverflowPolicy) *eventStream {
+	s := &eventStream{
+		limit:  size,
+		policy: policy,
+		out:    make(chan Event, size),
+		notify: make(chan struct{}, 1),
+	}
+	for i := 0; i < size; i++ {
+		s.out <- Event{}
+	}
+	return s
+}
+
+func TestEventStreamDropOldest(t *testing.T) {
+	s := stalledEventStream(2, EventOverflowDropOldest)
+
+	for i := 0; i < 6; i++ {
+		s.push(Event{ID: uint32(i), Payload: LogPayload{}})
+	}
+
+	require.Len(t, s.pending, 2)
+	require.Equal(t, uint32(4), s.pending[0].ID)
+	require.Equal(t, uint32(5), s.pending[1].ID)
+	require.Equal(t, uint64(4), s.droppedCount())
+}
+
+func TestEventStreamCoalesce(t *testing.T) {
+	s := stalledEventStream(2, EventOverflowCoalesce)
+
+	s.push(Event{ID: 0, Payload: LogPayload{}})
+	s.push(Event{ID: 1, Payload: LogPayload{}})
+
+	s.push(Event{ID: 2, Payload: CrackedPayload{Hash: "a"}})
+	s.push(Event{ID: 3, Payload: ActionPayload{}})
+	s.push(Event{ID: 4, Payload: ActionPayload{}})
+	s.push(Event{ID: 5, Payload: CrackedPayload{Hash: "b"}})
+	s.push(Event{ID: 6, Payload: CrackedPayload{Hash: "c"}})
+
+	ids := []uint32{}
+	for _, e := range s.pending {
+		ids = append(ids, e.ID)
+	}
+
+	// log and action events made way for the cracks, which are never dropped
+	require.Equal(t, []uint32{2, 5, 6}, ids)
+	require.Equal(t, uint64(4), s.droppedCount())
+}
+
+func TestEventStreamClose(t *testing.T) {
+	s := newEventStream(1, EventOverflowUnbounded)
+	s.push(Event{ID: 1})
+	s.close()
+	s.push(Event{ID: 2})
+
+	e, ok := <-s.out
+	require.True(t, ok)
+	require.Equal(t, uint32(1), e.ID)
+
+	_, ok = <-s.out
+	require.False(t, ok)
+}
+
+func TestEventsAfterClose(t *testing.T) {
+	hc := &Hashcat{}
+	hc.closeEvents()
+
+	select {
+	case _, ok := <-hc.Events():
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Events returned an open channel after the session was freed")
+	}
+	require.Nil(t, hc.events.Load())
+}
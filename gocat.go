@@ -10,11 +10,13 @@ package gocat
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -31,6 +33,20 @@ var (
 	// ErrUnableToStop is raised whenever we were unable to stop a hashcat session. At this time, hashcat's hashcat_session_quit
 	// always returns success so you'll most likely never see this.
 	ErrUnableToStop = errors.New("gocat: unable to stop task")
+	// ErrCancelledAtCheckpoint is returned by RunJobContext when the context was cancelled and the session stopped
+	// at its next checkpoint within Options.CancelGracePeriod
+	ErrCancelledAtCheckpoint = errors.New("gocat: session stopped at checkpoint after context was cancelled")
+	// ErrCancelledAborted is returned by RunJobContext when the context was cancelled and the session had to be aborted,
+	// either because it could not be checkpointed or because it did not stop within Options.CancelGracePeriod
+	ErrCancelledAborted = errors.New("gocat: session aborted after context was cancelled")
+	// ErrSessionNotRunning is raised when Pause or Bypass is called while no session is running
+	ErrSessionNotRunning = errors.New("gocat: session is not running")
+	// ErrSessionPaused is raised when Pause is called on a session that is already paused
+	ErrSessionPaused = errors.New("gocat: session is already paused")
+	// ErrSessionNotPaused is raised when Resume is called on a session that isn't paused
+	ErrSessionNotPaused = errors.New("gocat: session is not paused")
+	// ErrNoKeyspace is raised when hashcat finished a --keyspace session without reporting the keyspace
+	ErrNoKeyspace = errors.New("gocat: hashcat did not report a keyspace")
 
 	// Global map to associate ctx pointers with Hashcat instances
 	ctxMap      = make(map[uintptr]*Hashcat)
@@ -52,6 +68,10 @@ const (
 	sessionAbortedRuntime
 )
 
+// DefaultCancelGracePeriod is how long RunJobContext waits for a checkpoint before aborting the session
+// when Options.CancelGracePeriod is not set
+const DefaultCancelGracePeriod = 30 * time.Second
+
 // EventCallback defines the callback that hashcat/gocat calls
 type EventCallback func(Hashcat unsafe.Pointer, Payload interface{})
 
@@ -67,6 +87,23 @@ type Options struct {
 	// you to call several hashcat APIs (which fire another callback) from within an event callback.
 	// This is supported on macOS, Linux, and Windows.
 	PatchEventContext bool
+	// CancelGracePeriod is how long a session is given to reach its next checkpoint after the context passed into
+	// RunJobContext is cancelled. Once it expires the session is aborted. Defaults to DefaultCancelGracePeriod
+	CancelGracePeriod time.Duration
+	// EventBufferSize is the size of the channel returned by Hashcat.Events. Defaults to DefaultEventBufferSize
+	EventBufferSize int
+	// EventOverflowPolicy decides what happens to new events once the channel returned by Hashcat.Events is full
+	EventOverflowPolicy EventOverflowPolicy
+	// StatusInterval if set will emit a StatusPayload at this interval while hashcat is cracking.
+	// The payload is sent from a goroutine owned by gocat rather than one of hashcat's threads
+	// so it may be delivered concurrently with other events
+	StatusInterval time.Duration
+	// PotfileStore if set replaces hashcat's potfile (--potfile-disable is passed into hashcat) for sessions started by Run
+	// and receives every hash they crack. Jobs must use Job.Hashes, see PotfileStore for how it's used
+	PotfileStore PotfileStore
+	// RecoveryThreshold if set will stop a session at its next checkpoint (or abort it when --restore-disable is set)
+	// once it stops cracking enough hashes. JobResult.RecoveryStalled is set when this happens
+	RecoveryThreshold RecoveryThreshold
 }
 
 // ErrNoSharedPath is raised whenever Options.SharedPath is not set
@@ -93,6 +130,13 @@ func (o *Options) validate() (err error) {
 	return nil
 }
 
+func (o Options) cancelGracePeriod() time.Duration {
+	if o.CancelGracePeriod <= 0 {
+		return DefaultCancelGracePeriod
+	}
+	return o.CancelGracePeriod
+}
+
 // Hashcat is the interface which interfaces with libhashcat to provide password cracking capabilities.
 type Hashcat struct {
 	// unexported fields below
@@ -101,6 +145,11 @@ type Hashcat struct {
 	opts           Options
 	isEventPatched bool
 	l              sync.Mutex
+	job            atomic.Pointer[jobState]
+	events         atomic.Pointer[eventStream]
+	monitor        atomic.Pointer[sessionMonitor]
+	state          atomic.Int32
+	eventsOnce     sync.Once
 
 	// these must be free'd
 	executablePath *C.char
@@ -143,35 +192,174 @@ func (hc *Hashcat) EventCallbackIsReentrant() bool {
 }
 
 // RunJob starts a hashcat session and blocks until it has been finished.
-func (hc *Hashcat) RunJob(args ...string) (err error) {
+func (hc *Hashcat) RunJob(args ...string) error {
+	_, err := hc.run(args, newJobState())
+	return err
+}
+
+// RunJobWithOptions starts a hashcat session using opts and blocks until it has been finished.
+func (hc *Hashcat) RunJobWithOptions(opts hcargp.HashcatSessionOptions) error {
+	args, err := opts.MarshalArgs()
+	if err != nil {
+		return err
+	}
+	return hc.RunJob(args...)
+}
+
+// RunJobContext starts a hashcat session and blocks until it has been finished or ctx is done.
+// When ctx is cancelled the session is asked to stop at its next checkpoint and is aborted if it hasn't
+// stopped within Options.CancelGracePeriod. In that case the returned error wraps ctx.Err() along with
+// either ErrCancelledAtCheckpoint or ErrCancelledAborted.
+func (hc *Hashcat) RunJobContext(ctx context.Context, args ...string) error {
+	_, err := hc.Run(ctx, Job{Args: args})
+	return err
+}
+
+// RunJobWithOptionsContext is the context aware version of RunJobWithOptions. See RunJobContext.
+func (hc *Hashcat) RunJobWithOptionsContext(ctx context.Context, opts hcargp.HashcatSessionOptions) error {
+	_, err := hc.Run(ctx, Job{Options: &opts})
+	return err
+}
+
+// Run starts the hashcat session described by job and blocks until it has been finished or ctx is done.
+// Cancellation is handled the same way as RunJobContext. The returned JobResult describes how the session
+// ended and is set whenever hashcat executed the session, even if ctx was cancelled.
+func (hc *Hashcat) Run(ctx context.Context, job Job) (*JobResult, error) {
+	state := newJobState()
+	if store := hc.opts.PotfileStore; store != nil {
+		var allCracked bool
+		var err error
+		if job, allCracked, err = hc.usePotfileStore(store, job, state); err != nil {
+			return nil, err
+		} else if allCracked {
+			return state.finish(C.STATUS_CRACKED, sessionCracked), nil
+		}
+	}
+
+	args, cleanup, err := job.prepare()
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if job.Candidates != nil {
+		restore, err := redirectStdin(job.Candidates)
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
+	}
+
+	done := make(chan struct{})
+	cancelled := make(chan error, 1)
+	go hc.watchContext(ctx, done, cancelled)
+
+	state.originals = job.originalHashes()
+
+	res, err := hc.run(args, state)
+	close(done)
+
+	if flusher, ok := hc.opts.PotfileStore.(potfileStoreFlusher); ok && state.potfileStore {
+		if flushErr := flusher.Flush(); flushErr != nil && err == nil {
+			err = flushErr
+		}
+	}
+
+	if cancelErr := <-cancelled; cancelErr != nil {
+		return res, cancelErr
+	}
+	return res, err
+}
+
+// usePotfileStore replaces hashcat's potfile with store. The entries of job.Hashes that are already in store are reported
+// as potfile hits and removed from the returned job. allCracked is set when nothing is left to crack.
+// Hashes passed in through a hashfile can't be checked against store so ErrPotfileStoreWithoutHashes is returned for them
+func (hc *Hashcat) usePotfileStore(store PotfileStore, job Job, state *jobState) (_ Job, allCracked bool, err error) {
+	if job.Options == nil || len(job.Hashes) == 0 {
+		return job, false, ErrPotfileStoreWithoutHashes
+	}
+	state.potfileStore = true
+
+	opts := *job.Options
+	opts.PotfileDisable = hcargp.GetBoolPtr(true)
+	opts.PotfilePath = nil
+	job.Options = &opts
+
+	sep, usernames := job.hashFormat()
+	remaining := make([]string, 0, len(job.Hashes))
+	for _, entry := range job.Hashes {
+		hash, plain, ok, err := lookupPotfileStore(store, entry, sep, usernames)
+		if err != nil {
+			return job, false, err
+		}
+
+		if !ok {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		hc.emit(&hc.wrapper.ctx, 0, CrackedPayload{
+			IsPotfile: true,
+			Hash:      hash,
+			Value:     plain,
+			CrackedAt: time.Now().UTC(),
+			Original:  entry,
+		})
+	}
+
+	state.setPotfileHits(len(job.Hashes)-len(remaining), len(remaining) == 0)
+	job.Hashes = remaining
+	return job, len(remaining) == 0, nil
+}
+
+// run executes a hashcat session with args and blocks until it has been finished. job tracks the session
+// and receives every event from hashcat through its hook.
+func (hc *Hashcat) run(args []string, job *jobState) (*JobResult, error) {
 	hc.l.Lock()
 	defer hc.l.Unlock()
 
+	job.history = newRecoveryHistory(hc.opts.RecoveryThreshold.Window)
+	hc.job.Store(job)
+
+	hc.setState(0, StateInitializing)
+	defer hc.setState(0, StateFinished)
+
 	// initialize the default options in hashcat_ctx->user_options
 	if retval := C.user_options_init(&hc.wrapper.ctx); retval != 0 {
-		return
+		return nil, job.sessionError(getErrorFromCtx(hc.wrapper.ctx))
 	}
 
 	argc, argv := convertArgsToC(append([]string{hc.opts.ExecutablePath}, args...)...)
 	defer C.freeargv(argc, argv)
 
 	if retval := C.user_options_getopt(&hc.wrapper.ctx, argc, argv); retval != 0 {
-		return getErrorFromCtx(hc.wrapper.ctx)
+		return nil, job.sessionError(getErrorFromCtx(hc.wrapper.ctx))
 	}
 
 	if retval := C.user_options_sanity(&hc.wrapper.ctx); retval != 0 {
-		return getErrorFromCtx(hc.wrapper.ctx)
+		return nil, job.sessionError(getErrorFromCtx(hc.wrapper.ctx))
 	}
 
 	if retval := C.hashcat_session_init(&hc.wrapper.ctx, hc.executablePath, hc.sharedPath, argc, argv, C.int(CompileTime)); retval != 0 {
-		return getErrorFromCtx(hc.wrapper.ctx)
+		return nil, job.sessionError(getErrorFromCtx(hc.wrapper.ctx))
 	}
 	defer C.hashcat_session_destroy(&hc.wrapper.ctx)
+	// the monitor must be stopped before the session is destroyed in case hashcat bailed out of its outer loop
+	defer hc.stopMonitor()
+
+	if job.initOnly != nil {
+		job.initOnly(&hc.wrapper.ctx)
+		return job.finish(hc.wrapper.ctx.status_ctx.devices_status, sessionCracked), nil
+	}
 
 	if hc.opts.PatchEventContext {
 		isPatchSuccessful, err := patchEventMutex(hc.wrapper.ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		hc.isEventPatched = isPatchSuccessful
 	}
@@ -180,20 +368,57 @@ func (hc *Hashcat) RunJob(args ...string) (err error) {
 	switch int(rc) {
 	case sessionCracked, sessionExhausted, sessionQuit, sessionAborted,
 		sessionAbortedCheckpoint, sessionAbortedRuntime:
-		err = nil
 	default:
-		return getErrorFromCtx(hc.wrapper.ctx)
+		return nil, job.sessionError(getErrorFromCtx(hc.wrapper.ctx))
 	}
 
-	return
+	return job.finish(hc.wrapper.ctx.status_ctx.devices_status, int(rc)), nil
 }
 
-func (hc *Hashcat) RunJobWithOptions(opts hcargp.HashcatSessionOptions) error {
-	args, err := opts.MarshalArgs()
-	if err != nil {
-		return err
+// watchContext stops the running session once ctx is done. It sends nil on cancelled if the session finished
+// on its own, otherwise an error describing how the session was stopped.
+func (hc *Hashcat) watchContext(ctx context.Context, done <-chan struct{}, cancelled chan<- error) {
+	select {
+	case <-done:
+		cancelled <- nil
+		return
+	case <-ctx.Done():
+		// select picks randomly when both are ready, a session that already finished wasn't cancelled
+		select {
+		case <-done:
+			cancelled <- nil
+			return
+		default:
+		}
+	}
+
+	// StopAtCheckpoint fails if the session isn't running yet or --restore-disable is set, go straight to aborting
+	if err := hc.StopAtCheckpoint(); err == nil {
+		timer := time.NewTimer(hc.opts.cancelGracePeriod())
+		defer timer.Stop()
+
+		select {
+		case <-done:
+			cancelled <- fmt.Errorf("%w: %w", ErrCancelledAtCheckpoint, ctx.Err())
+			return
+		case <-timer.C:
+		}
+	}
+
+	// hashcat can miss a quit request that arrives while the session is still initializing so keep asking until it stops
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		hc.AbortRunningTask()
+
+		select {
+		case <-done:
+			cancelled <- fmt.Errorf("%w: %w", ErrCancelledAborted, ctx.Err())
+			return
+		case <-ticker.C:
+		}
 	}
-	return hc.RunJob(args...)
 }
 
 // IdentifyHash will identify the hash type of a given hash. Returns a list of types if successful.
@@ -303,9 +528,69 @@ func IdentifyHash(hash string, options Options, hasUsername bool) (hashtypes []t
 
 // StopAtCheckpoint instructs the running hashcat session to stop at the next available checkpoint
 func (hc *Hashcat) StopAtCheckpoint() error {
+	switch state := hc.State(); state {
+	case StateRunning:
+	case StateCheckpointing:
+		// hashcat toggles the checkpoint on every call so don't ask twice
+		return nil
+	default:
+		return &StateError{Op: "stop at checkpoint", State: state, Err: ErrUnableToStopAtCheckpoint}
+	}
+
 	if retval := C.hashcat_session_checkpoint(&hc.wrapper.ctx); retval != 0 {
 		return ErrUnableToStopAtCheckpoint
 	}
+	hc.setState(0, StateCheckpointing)
+	return nil
+}
+
+// Pause suspends the running session until Resume is called. Devices stay idle while the session is paused
+func (hc *Hashcat) Pause() error {
+	switch state := hc.State(); state {
+	case StateRunning:
+	case StatePaused:
+		return &StateError{Op: "pause", State: state, Err: ErrSessionPaused}
+	default:
+		return &StateError{Op: "pause", State: state, Err: ErrSessionNotRunning}
+	}
+
+	if retval := C.hashcat_session_pause(&hc.wrapper.ctx); retval != 0 {
+		return ErrSessionNotRunning
+	}
+	hc.setState(0, StatePaused)
+	return nil
+}
+
+// Resume continues a session that was suspended with Pause
+func (hc *Hashcat) Resume() error {
+	if state := hc.State(); state != StatePaused {
+		return &StateError{Op: "resume", State: state, Err: ErrSessionNotPaused}
+	}
+
+	if retval := C.hashcat_session_resume(&hc.wrapper.ctx); retval != 0 {
+		return ErrSessionNotPaused
+	}
+
+	if job := hc.job.Load(); job != nil {
+		job.restartRecoveryWindow()
+	}
+	hc.setState(0, StateRunning)
+	return nil
+}
+
+// Bypass instructs hashcat to skip the rest of the current dictionary or mask and move on to the next one in the queue
+func (hc *Hashcat) Bypass() error {
+	switch state := hc.State(); state {
+	case StateRunning:
+	case StatePaused:
+		return &StateError{Op: "bypass", State: state, Err: ErrSessionPaused}
+	default:
+		return &StateError{Op: "bypass", State: state, Err: ErrSessionNotRunning}
+	}
+
+	if retval := C.hashcat_session_bypass(&hc.wrapper.ctx); retval != 0 {
+		return ErrSessionNotRunning
+	}
 	return nil
 }
 
@@ -314,9 +599,10 @@ func (hc *Hashcat) AbortRunningTask() {
 	C.hashcat_session_quit(&hc.wrapper.ctx)
 }
 
+// getErrorFromCtx returns the last error logged by hashcat as a *HashcatError
 func getErrorFromCtx(ctx C.hashcat_ctx_t) error {
 	msg := C.hashcat_get_log(&ctx)
-	return fmt.Errorf("gocat: %s", C.GoString(msg))
+	return newHashcatError(C.GoString(msg))
 }
 
 //export callback
@@ -331,6 +617,17 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 		return
 	}
 
+	if job := ctx.job.Load(); job != nil && job.hook != nil {
+		job.hook(id, hcCtx, buf)
+	}
+
+	if state, ok := stateFromEvent(id); ok {
+		// Pausing or checkpointing takes precedence over hashcat moving between autotuning and cracking
+		if current := ctx.State(); current != StatePaused && current != StateCheckpointing {
+			ctx.setState(id, state)
+		}
+	}
+
 	var payload interface{}
 	var err error
 
@@ -342,6 +639,7 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 		if payload.(LogPayload).Message == "" && hcCtx != nil && hcCtx.event_ctx != nil {
 			payload = logMessageCbFromEvent(hcCtx, ErrorMessage)
 		}
+		payload = withParsedError(payload.(LogPayload))
 	case C.EVENT_LOG_INFO:
 		// In hashcat 7.x+, log messages are usually passed via buf
 		// but for --identify mode, check event_ctx if buf is empty
@@ -356,6 +654,13 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 		if payload.(LogPayload).Message == "" && hcCtx != nil && hcCtx.event_ctx != nil {
 			payload = logMessageCbFromEvent(hcCtx, WarnMessage)
 		}
+		payload = withParsedError(payload.(LogPayload))
+
+		// hashcat logs a warning for every hash it rejects before failing with "No hashes loaded"
+		var pe *HashParseError
+		if job := ctx.job.Load(); job != nil && errors.As(payload.(LogPayload).Error, &pe) {
+			job.addParseError(pe)
+		}
 	case C.EVENT_BITMAP_INIT_PRE:
 		payload = logHashcatAction(id, "Generating bitmap tables")
 	case C.EVENT_BITMAP_INIT_POST:
@@ -391,6 +696,9 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 		payload = logHashcatAction(id, "Compared hashes with potfile entries")
 	case C.EVENT_POTFILE_ALL_CRACKED:
 		payload = logHashcatAction(id, "All hashes exist in potfile")
+		if job := ctx.job.Load(); job != nil && hcCtx != nil {
+			job.setPotfileHits(int(hcCtx.hashes.digests_done), true)
+		}
 		if ctx.isEventPatched {
 			C.potfile_handle_show(&ctx.wrapper.ctx)
 		}
@@ -407,25 +715,67 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 			if ctxHashes.digests_done > 0 {
 				payload = logHashcatAction(id, fmt.Sprintf("Removed %d hash(s) found in potfile", ctxHashes.digests_done))
 			}
+
+			if job := ctx.job.Load(); job != nil {
+				job.setPotfileHits(int(ctxHashes.digests_done), false)
+			}
 		}
 	case C.EVENT_CRACKER_HASH_CRACKED, C.EVENT_POTFILE_HASH_SHOW:
-		// Grab the separator for this session out of user options
-		sepr := ":"
-		if hcCtx != nil {
-			userOpts := hcCtx.user_options
-			if userOpts.separator != nil {
-				sepr = C.GoString(userOpts.separator)
+		var pl CrackedPayload
+		if pl, err = crackedPasswordFromEvent(id, hcCtx, buf); err != nil {
+			payload = logMessageWithError(id, err)
+			break
+		}
+
+		if job := ctx.job.Load(); job != nil {
+			pl.Original = job.originalHash(pl.Hash)
+			if id == C.EVENT_CRACKER_HASH_CRACKED {
+				job.addCracked()
 			}
 		}
 
-		msg := C.GoString((*C.char)(buf))
-		if payload, err = getCrackedPassword(id, msg, sepr); err != nil {
-			payload = logMessageWithError(id, err)
+		if job := ctx.job.Load(); job != nil && job.potfileStore && id == C.EVENT_CRACKER_HASH_CRACKED {
+			if err := ctx.opts.PotfileStore.Add(pl.Hash, pl.Value); err != nil {
+				ctx.emit(hcCtx, id, LogPayload{
+					Level:   WarnMessage,
+					Message: fmt.Sprintf("Failed to add cracked hash to the potfile store: %s", err),
+					Error:   err,
+				})
+			}
+		}
+		payload = pl
+	case C.EVENT_POTFILE_HASH_LEFT:
+		payload = leftHashFromEvent(hcCtx, buf)
+	case C.EVENT_OUTERLOOP_STARTING:
+		if job := ctx.job.Load(); job != nil {
+			job.markCrackingStarted()
 		}
+		ctx.startMonitor()
 	case C.EVENT_OUTERLOOP_FINISHED:
+		ctx.stopMonitor()
+
+		var status *Status
+		var snapshot *StatusSnapshot
+		readStatus(&ctx.wrapper.ctx, func(hcStatus *C.hashcat_status_t) {
+			hwmon := hwmonFromC(hcStatus)
+			status = statusFromC(hcStatus, hwmon)
+			snapshot = snapshotFromC(&ctx.wrapper.ctx, hcStatus, hwmon)
+		})
+
+		if status != nil {
+			rate := ctx.recoveryRate()
+			status.RecoveryRate = rate
+			snapshot.RecoveryRate = rate
+		}
+
+		if job := ctx.job.Load(); job != nil {
+			job.setFinalStatus(status, snapshot)
+		}
+
 		payload = FinalStatusPayload{
-			Status:  ctx.GetStatus(),
-			EndedAt: time.Now().UTC(),
+			Status:   status,
+			Snapshot: snapshot,
+			EndedAt:  time.Now().UTC(),
 		}
 
 	}
@@ -434,9 +784,24 @@ func callback(id uint32, hcCtx *C.hashcat_ctx_t, wrapper unsafe.Pointer, buf uns
 	// EVENT_CRACKER_STARTING
 	// EVENT_OUTERLOOP_MAINSCREEN
 
-	// Only call callback if we have a payload to send and a valid callback
-	if payload != nil && ctx != nil && ctx.cb != nil {
-		ctx.cb(unsafe.Pointer(hcCtx), payload)
+	// Only emit an event if we have a payload to send
+	if payload != nil && ctx != nil {
+		ctx.emit(hcCtx, id, payload)
+	}
+}
+
+// emit sends payload to the EventCallback and the Events channel
+func (hc *Hashcat) emit(hcCtx *C.hashcat_ctx_t, id uint32, payload interface{}) {
+	if s := hc.events.Load(); s != nil {
+		s.push(Event{
+			ID:      id,
+			Time:    time.Now().UTC(),
+			Payload: payload,
+		})
+	}
+
+	if hc.cb != nil {
+		hc.cb(unsafe.Pointer(hcCtx), payload)
 	}
 }
 
@@ -447,6 +812,8 @@ func (hc *Hashcat) Free() {
 	delete(ctxMap, uintptr(unsafe.Pointer(&hc.wrapper.ctx)))
 	ctxMapMutex.Unlock()
 
+	hc.closeEvents()
+
 	C.hashcat_destroy(&hc.wrapper.ctx)
 	C.free(unsafe.Pointer(hc.executablePath))
 	C.free(unsafe.Pointer(hc.sharedPath))
@@ -4,14 +4,35 @@ package gocat
 import "C"
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
 	"unsafe"
 )
 
+// DeviceHWMon contains the hardware monitoring data of a device.
+// Values are -1 when the device doesn't report them or hwmon is disabled (--hwmon-disable)
+type DeviceHWMon struct {
+	// Temperature is in celsius
+	Temperature int
+	// FanSpeed is a percentage
+	FanSpeed int
+	// Utilization is a percentage
+	Utilization int
+	// CoreClock is in MHz
+	CoreClock int
+	// MemoryClock is in MHz
+	MemoryClock int
+	PCIeLanes   int
+}
+
 // DeviceStatus contains information about the OpenCL device that is cracking
 type DeviceStatus struct {
 	DeviceID  int
 	HashesSec string
 	ExecDev   float64
+	HWMon     DeviceHWMon
 }
 
 // Status contains data about the current cracking session
@@ -25,18 +46,54 @@ type Status struct {
 	TimeEstimatedRelative string
 	DeviceStatus          []DeviceStatus
 	TotalSpeed            string
-	ProgressMode          int
+	ProgressMode          ProgressMode
 	Candidates            map[int]string // map[DeviceID]string
 	Progress              string
 	Rejected              string
 	Recovered             string
 	RestorePoint          string
-	GuessMode             int
+	GuessMode             GuessMode
 	GuessMask             string `json:",omitempty"`
 	GuessQueue            string `json:",omitempty"`
 	GuessBase             string `json:",omitempty"`
 	GuessMod              string `json:",omitempty"`
 	GuessCharset          string `json:",omitempty"`
+	// RecoveryRate is tracked by gocat from the hashes cracked during the session
+	RecoveryRate RecoveryRate
+}
+
+// DeviceSnapshot contains the raw numbers about a device that is cracking
+type DeviceSnapshot struct {
+	DeviceID     int
+	HashesPerSec float64
+	// ExecTime is the average kernel execution time of the device
+	ExecTime time.Duration
+	HWMon    DeviceHWMon
+}
+
+// StatusSnapshot contains the same data as Status but as raw numbers rather than formatted strings
+type StatusSnapshot struct {
+	ProgressMode    ProgressMode
+	ProgressCurrent uint64
+	ProgressEnd     uint64
+	ProgressPercent float64
+	Rejected        uint64
+	RejectedPercent float64
+	RestorePoint    uint64
+	RestoreTotal    uint64
+	RestorePercent  float64
+	DigestsDone     int
+	DigestsTotal    int
+	SaltsDone       int
+	SaltsTotal      int
+	Devices         []DeviceSnapshot
+	// TotalHashesPerSec is the combined speed of all devices
+	TotalHashesPerSec float64
+	StartedAt         time.Time
+	// ETA is the estimated time left until the keyspace is exhausted. This is zero if the keyspace is unknown
+	// or if hashcat hasn't measured a speed yet
+	ETA          time.Duration
+	RecoveryRate RecoveryRate
 }
 
 var szStatusStruct = unsafe.Sizeof(C.hashcat_status_t{})
@@ -44,17 +101,44 @@ var szStatusStruct = unsafe.Sizeof(C.hashcat_status_t{})
 // GetStatus returns the status of the cracking.
 // This is an implementation of https://github.com/hashcat/hashcat/blob/master/src/terminal.c#L709
 func (hc *Hashcat) GetStatus() *Status {
+	var stats *Status
+	readStatus(&hc.wrapper.ctx, func(hcStatus *C.hashcat_status_t) {
+		stats = statusFromC(hcStatus, hwmonFromC(hcStatus))
+		stats.RecoveryRate = hc.recoveryRate()
+	})
+	return stats
+}
+
+// GetStatusSnapshot returns the status of the cracking as raw numbers.
+// It returns nil if hashcat is unable to provide a status (usually because the session isn't running)
+func (hc *Hashcat) GetStatusSnapshot() *StatusSnapshot {
+	var snapshot *StatusSnapshot
+	readStatus(&hc.wrapper.ctx, func(hcStatus *C.hashcat_status_t) {
+		snapshot = snapshotFromC(&hc.wrapper.ctx, hcStatus, hwmonFromC(hcStatus))
+		snapshot.RecoveryRate = hc.recoveryRate()
+	})
+	return snapshot
+}
+
+// readStatus fetches the status of the session from hashcat and passes it into fn.
+// It returns false if hashcat was unable to provide a status (usually because the session isn't running)
+func readStatus(ctx *C.hashcat_ctx_t, fn func(hcStatus *C.hashcat_status_t)) bool {
 	ptr := C.malloc(C.size_t(szStatusStruct))
 	hcStatus := (*C.hashcat_status_t)(unsafe.Pointer(ptr))
 	defer func() {
-		C.status_status_destroy(&hc.wrapper.ctx, hcStatus)
+		C.status_status_destroy(ctx, hcStatus)
 		C.free(unsafe.Pointer(hcStatus))
 	}()
 
-	if retval := C.hashcat_get_status(&hc.wrapper.ctx, hcStatus); retval != 0 {
-		return nil
+	if retval := C.hashcat_get_status(ctx, hcStatus); retval != 0 {
+		return false
 	}
 
+	fn(hcStatus)
+	return true
+}
+
+func statusFromC(hcStatus *C.hashcat_status_t, hwmon []DeviceHWMon) *Status {
 	stats := &Status{
 		Session:               C.GoString(hcStatus.session),
 		Status:                C.GoString(hcStatus.status_string),
@@ -67,13 +151,13 @@ func (hc *Hashcat) GetStatus() *Status {
 		// to avoid having empty DeviceStatus's
 		DeviceStatus: make([]DeviceStatus, 0),
 		TotalSpeed:   C.GoString(hcStatus.speed_sec_all),
-		ProgressMode: int(hcStatus.progress_mode),
+		ProgressMode: ProgressMode(hcStatus.progress_mode),
 		Candidates:   make(map[int]string),
-		GuessMode:    int(hcStatus.guess_mode),
+		GuessMode:    GuessMode(hcStatus.guess_mode),
 	}
 
 	switch stats.ProgressMode {
-	case C.PROGRESS_MODE_KEYSPACE_KNOWN:
+	case ProgressModeKeyspaceKnown:
 		stats.Progress = fmt.Sprintf("%d/%d (%.02f%%)", hcStatus.progress_cur_relative_skip,
 			hcStatus.progress_end_relative_skip,
 			hcStatus.progress_finished_percent)
@@ -83,54 +167,54 @@ func (hc *Hashcat) GetStatus() *Status {
 		stats.RestorePoint = fmt.Sprintf("%d/%d (%.02f%%)", hcStatus.restore_point,
 			hcStatus.restore_total,
 			hcStatus.restore_percent)
-	case C.PROGRESS_MODE_KEYSPACE_UNKNOWN:
+	case ProgressModeKeyspaceUnknown:
 		stats.Progress = fmt.Sprintf("%d", hcStatus.progress_cur_relative_skip)
 		stats.Rejected = fmt.Sprintf("%d", hcStatus.progress_rejected)
 		stats.RestorePoint = fmt.Sprintf("%d", hcStatus.restore_point)
 	}
 
 	switch stats.GuessMode {
-	case C.GUESS_MODE_STRAIGHT_FILE:
+	case GuessModeStraightFile:
 		stats.GuessBase = C.GoString(hcStatus.guess_base)
-	case C.GUESS_MODE_STRAIGHT_FILE_RULES_FILE:
+	case GuessModeStraightFileRulesFile:
 		stats.GuessBase = C.GoString(hcStatus.guess_base)
 		stats.GuessMod = C.GoString(hcStatus.guess_mod)
-	case C.GUESS_MODE_STRAIGHT_FILE_RULES_GEN:
+	case GuessModeStraightFileRulesGen:
 		stats.GuessBase = C.GoString(hcStatus.guess_base)
 		stats.GuessMod = "Rules (Generated)"
-	case C.GUESS_MODE_STRAIGHT_STDIN:
+	case GuessModeStraightStdin:
 		stats.GuessBase = "Pipe"
-	case C.GUESS_MODE_STRAIGHT_STDIN_RULES_FILE:
+	case GuessModeStraightStdinRulesFile:
 		stats.GuessBase = "Pipe"
 		stats.GuessMod = C.GoString(hcStatus.guess_mod)
-	case C.GUESS_MODE_STRAIGHT_STDIN_RULES_GEN:
+	case GuessModeStraightStdinRulesGen:
 		stats.GuessBase = "Pipe"
 		stats.GuessMod = "Rules (Generated)"
-	case C.GUESS_MODE_COMBINATOR_BASE_LEFT:
+	case GuessModeCombinatorBaseLeft:
 		stats.GuessBase = fmt.Sprintf("File (%s), Left Side", C.GoString(hcStatus.guess_base))
 		stats.GuessMod = fmt.Sprintf("File (%s), Right Side", C.GoString(hcStatus.guess_mod))
-	case C.GUESS_MODE_COMBINATOR_BASE_RIGHT:
+	case GuessModeCombinatorBaseRight:
 		stats.GuessBase = fmt.Sprintf("File (%s), Right Side", C.GoString(hcStatus.guess_base))
 		stats.GuessMod = fmt.Sprintf("File (%s), Left Side", C.GoString(hcStatus.guess_mod))
-	case C.GUESS_MODE_MASK:
+	case GuessModeMask:
 		stats.GuessMask = fmt.Sprintf("%s [%d]", C.GoString(hcStatus.guess_base), int(hcStatus.guess_mask_length))
-	case C.GUESS_MODE_MASK_CS:
+	case GuessModeMaskCS:
 		stats.GuessMask = fmt.Sprintf("%s [%d]", C.GoString(hcStatus.guess_base), int(hcStatus.guess_mask_length))
 		stats.GuessCharset = C.GoString(hcStatus.guess_charset)
-	case C.GUESS_MODE_HYBRID1_CS:
+	case GuessModeHybrid1CS:
 		stats.GuessCharset = C.GoString(hcStatus.guess_charset)
 		fallthrough // grab GuessBase/GuessMod from below as it's the same
-	case C.GUESS_MODE_HYBRID1:
+	case GuessModeHybrid1:
 		stats.GuessBase = fmt.Sprintf("File (%s), Left Side", C.GoString(hcStatus.guess_base))
 		stats.GuessMod = fmt.Sprintf("Mask (%s) [%d], Right Side", C.GoString(hcStatus.guess_base), int(hcStatus.guess_mask_length))
 	}
 
 	switch stats.GuessMode {
-	case C.GUESS_MODE_STRAIGHT_FILE, C.GUESS_MODE_STRAIGHT_FILE_RULES_FILE,
-		C.GUESS_MODE_STRAIGHT_FILE_RULES_GEN, C.GUESS_MODE_MASK:
+	case GuessModeStraightFile, GuessModeStraightFileRulesFile,
+		GuessModeStraightFileRulesGen, GuessModeMask:
 		stats.GuessQueue = fmt.Sprintf("%d/%d (%.02f%%)", hcStatus.guess_base_offset,
 			hcStatus.guess_base_count, hcStatus.guess_base_percent)
-	case C.GUESS_MODE_HYBRID1, C.GUESS_MODE_HYBRID2:
+	case GuessModeHybrid1, GuessModeHybrid2:
 		stats.GuessQueue = fmt.Sprintf("%d/%d (%.02f%%)", hcStatus.guess_base_offset,
 			hcStatus.guess_base_count, hcStatus.guess_base_percent)
 	}
@@ -154,6 +238,7 @@ func (hc *Hashcat) GetStatus() *Status {
 			DeviceID:  i + 1,
 			HashesSec: C.GoString(deviceInfo.speed_sec_dev),
 			ExecDev:   float64(deviceInfo.exec_msec_dev),
+			HWMon:     hwmon[i],
 		})
 
 		if deviceInfo.guess_candidates_dev != nil {
@@ -163,3 +248,110 @@ func (hc *Hashcat) GetStatus() *Status {
 
 	return stats
 }
+
+func snapshotFromC(ctx *C.hashcat_ctx_t, hcStatus *C.hashcat_status_t, hwmon []DeviceHWMon) *StatusSnapshot {
+	snapshot := &StatusSnapshot{
+		ProgressMode:      ProgressMode(hcStatus.progress_mode),
+		ProgressCurrent:   uint64(hcStatus.progress_cur_relative_skip),
+		ProgressEnd:       uint64(hcStatus.progress_end_relative_skip),
+		ProgressPercent:   float64(hcStatus.progress_finished_percent),
+		Rejected:          uint64(hcStatus.progress_rejected),
+		RejectedPercent:   float64(hcStatus.progress_rejected_percent),
+		RestorePoint:      uint64(hcStatus.restore_point),
+		RestoreTotal:      uint64(hcStatus.restore_total),
+		RestorePercent:    float64(hcStatus.restore_percent),
+		DigestsDone:       int(hcStatus.digests_done),
+		DigestsTotal:      int(hcStatus.digests_cnt),
+		SaltsDone:         int(hcStatus.salts_done),
+		SaltsTotal:        int(hcStatus.salts_cnt),
+		Devices:           make([]DeviceSnapshot, 0),
+		TotalHashesPerSec: float64(hcStatus.hashes_msec_all) * 1000,
+	}
+
+	if ctx.status_ctx != nil && ctx.status_ctx.runtime_start != 0 {
+		snapshot.StartedAt = time.Unix(int64(ctx.status_ctx.runtime_start), 0).UTC()
+	}
+
+	if snapshot.ProgressMode == ProgressModeKeyspaceKnown {
+		snapshot.ETA = estimateTimeLeft(snapshot.ProgressCurrent, snapshot.ProgressEnd, float64(hcStatus.hashes_msec_all))
+	}
+
+	for i := 0; i < int(hcStatus.device_info_cnt); i++ {
+		deviceInfo := hcStatus.device_info_buf[i]
+		if deviceInfo.skipped_dev {
+			continue
+		}
+
+		snapshot.Devices = append(snapshot.Devices, DeviceSnapshot{
+			DeviceID:     i + 1,
+			HashesPerSec: float64(deviceInfo.hashes_msec_dev) * 1000,
+			ExecTime:     time.Duration(float64(deviceInfo.exec_msec_dev) * float64(time.Millisecond)),
+			HWMon:        hwmon[i],
+		})
+	}
+
+	return snapshot
+}
+
+// hwmonFromC returns the hardware monitoring data hashcat_get_status collected for every device in hcStatus,
+// indexed like hcStatus.device_info_buf
+func hwmonFromC(hcStatus *C.hashcat_status_t) []DeviceHWMon {
+	hwmon := make([]DeviceHWMon, int(hcStatus.device_info_cnt))
+	for i := range hwmon {
+		deviceInfo := &hcStatus.device_info_buf[i]
+
+		hwmon[i] = parseHWMon(C.GoString(deviceInfo.hwmon_dev))
+		hwmon[i].CoreClock = int(deviceInfo.corespeed_dev)
+		hwmon[i].MemoryClock = int(deviceInfo.memoryspeed_dev)
+	}
+	return hwmon
+}
+
+// hwmonPattern matches the readings of a device's hwmon_dev which is formatted by status_get_hwmon_dev in hashcat's status.c,
+// e.g. "Temp: 65c Fan: 40% Util: 99% Core:1875MHz Mem:7000MHz Bus:16". Readings the device doesn't report are left out.
+// The clocks are read from corespeed_dev and memoryspeed_dev instead
+var hwmonPattern = regexp.MustCompile(`(Temp|Fan|Util|Bus):\s*(\d+)`)
+
+// parseHWMon parses the hwmon_dev string of a device, missing readings are set to -1
+func parseHWMon(s string) DeviceHWMon {
+	hwmon := DeviceHWMon{
+		Temperature: -1,
+		FanSpeed:    -1,
+		Utilization: -1,
+		CoreClock:   -1,
+		MemoryClock: -1,
+		PCIeLanes:   -1,
+	}
+
+	for _, match := range hwmonPattern.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+
+		switch match[1] {
+		case "Temp":
+			hwmon.Temperature = n
+		case "Fan":
+			hwmon.FanSpeed = n
+		case "Util":
+			hwmon.Utilization = n
+		case "Bus":
+			hwmon.PCIeLanes = n
+		}
+	}
+	return hwmon
+}
+
+// estimateTimeLeft returns how long it will take to get from cur to end at hashesMsec (hashes per millisecond)
+func estimateTimeLeft(cur, end uint64, hashesMsec float64) time.Duration {
+	if hashesMsec <= 0 || cur >= end {
+		return 0
+	}
+
+	msLeft := float64(end-cur) / hashesMsec
+	if msLeft >= float64(math.MaxInt64/int64(time.Millisecond)) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(msLeft * float64(time.Millisecond))
+}
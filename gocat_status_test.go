@@ -0,0 +1,81 @@
+package gocat
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTimeLeft(t *testing.T) {
+	for _, test := range []struct {
+		cur, end   uint64
+		hashesMsec float64
+		expected   time.Duration
+	}{
+		{cur: 0, end: 1000, hashesMsec: 1, expected: time.Second},
+		{cur: 500, end: 1000, hashesMsec: 10, expected: 50 * time.Millisecond},
+		{cur: 1000, end: 1000, hashesMsec: 10, expected: 0},
+		{cur: 0, end: 1000, hashesMsec: 0, expected: 0},
+		{cur: 0, end: math.MaxUint64, hashesMsec: 0.001, expected: time.Duration(math.MaxInt64)},
+	} {
+		assert.Equal(t, test.expected, estimateTimeLeft(test.cur, test.end, test.hashesMsec))
+	}
+}
+
+func TestParseHWMon(t *testing.T) {
+	assert.Equal(t, DeviceHWMon{
+		Temperature: 65,
+		FanSpeed:    40,
+		Utilization: 99,
+		CoreClock:   -1,
+		MemoryClock: -1,
+		PCIeLanes:   16,
+	}, parseHWMon("Temp: 65c Fan: 40% Util: 99% Core:1875MHz Mem:7000MHz Bus:16"))
+
+	assert.Equal(t, DeviceHWMon{
+		Temperature: 48,
+		FanSpeed:    -1,
+		Utilization: 100,
+		CoreClock:   -1,
+		MemoryClock: -1,
+		PCIeLanes:   -1,
+	}, parseHWMon("Temp: 48c Util:100%"))
+
+	assert.Equal(t, DeviceHWMon{-1, -1, -1, -1, -1, -1}, parseHWMon("N/A"))
+	assert.Equal(t, DeviceHWMon{-1, -1, -1, -1, -1, -1}, parseHWMon(""))
+}
+
+func TestStatusModes(t *testing.T) {
+	assert.Equal(t, "Wordlist + Rules", GuessModeStraightFileRulesFile.String())
+	assert.Equal(t, "Hybrid Mask + Wordlist, Custom Charset", GuessModeHybrid2CS.String())
+	assert.Equal(t, "GuessMode(99)", GuessMode(99).String())
+
+	assert.Equal(t, "Keyspace Unknown", ProgressModeKeyspaceUnknown.String())
+	assert.Equal(t, "ProgressMode(99)", ProgressMode(99).String())
+
+	guessMode, err := ParseGuessMode(" wordlist + rules ")
+	require.NoError(t, err)
+	assert.Equal(t, GuessModeStraightFileRulesFile, guessMode)
+
+	guessMode, err = ParseGuessMode(strconv.Itoa(int(GuessModeMask)))
+	require.NoError(t, err)
+	assert.Equal(t, GuessModeMask, guessMode)
+
+	_, err = ParseGuessMode("99")
+	assert.EqualError(t, err, "invalid guess mode 99")
+
+	progressMode, err := ParseProgressMode("Keyspace Known")
+	require.NoError(t, err)
+	assert.Equal(t, ProgressModeKeyspaceKnown, progressMode)
+
+	progressMode, err = ParseProgressMode(strconv.Itoa(int(ProgressModeKeyspaceUnknown)))
+	require.NoError(t, err)
+	assert.Equal(t, ProgressModeKeyspaceUnknown, progressMode)
+
+	_, err = ParseProgressMode("fast")
+	assert.EqualError(t, err, `invalid progress mode "fast"`)
+}
@@ -1,11 +1,17 @@
 package gocat
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/niall-san/gocat/v7/hcargp"
@@ -153,11 +159,11 @@ func TestGoCatRunJobWithOptions(t *testing.T) {
 		OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
 		SessionName:                  hcargp.GetStringPtr("test3"),
 		OptimizedKernelEnabled:       hcargp.GetBoolPtr(true),
-		AttackMode:                   hcargp.GetIntPtr(0),
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
 		HashType:                     hcargp.GetIntPtr(0),
 		PotfileDisable:               hcargp.GetBoolPtr(true),
 		InputFile:                    "9f9d51bc70ef21ca5c14f307980a29d8",
-		DictionaryMaskDirectoryInput: hcargp.GetStringPtr("./testdata/test_dictionary.txt"),
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
 	})
 
 	require.NoError(t, err)
@@ -165,6 +171,151 @@ func TestGoCatRunJobWithOptions(t *testing.T) {
 	require.Equal(t, "bob", *crackedHashes["9f9d51bc70ef21ca5c14f307980a29d8"])
 }
 
+func TestGoCatRunReturnsResult(t *testing.T) {
+	crackedHashes := map[string]*string{}
+
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(crackedHashes))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	res, err := hc.Run(context.Background(), Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+			SessionName:                  hcargp.GetStringPtr("test8"),
+			OptimizedKernelEnabled:       hcargp.GetBoolPtr(true),
+			AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:                     hcargp.GetIntPtr(0),
+			PotfileDisable:               hcargp.GetBoolPtr(true),
+			InputFile:                    "./testdata/two_md5.hashes",
+			DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.Equal(t, OutcomeCracked, res.Outcome)
+	require.Equal(t, 2, res.Cracked)
+	require.Equal(t, 0, res.PotfileHits)
+	require.NotNil(t, res.Status)
+	require.NotNil(t, res.Snapshot)
+	require.Equal(t, 2, res.Snapshot.DigestsDone)
+	require.Equal(t, 2, res.Snapshot.DigestsTotal)
+	require.NotEmpty(t, res.Snapshot.Devices)
+	require.False(t, res.Snapshot.StartedAt.IsZero())
+	for _, dev := range res.Snapshot.Devices {
+		// -1 is reported for anything the device doesn't support
+		require.GreaterOrEqual(t, dev.HWMon.Temperature, -1)
+		require.GreaterOrEqual(t, dev.HWMon.Utilization, -1)
+	}
+	require.False(t, res.EndedAt.Before(res.StartedAt))
+}
+
+func TestGoCatEventsChannel(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath:          DefaultSharedPath,
+		EventBufferSize:     8,
+		EventOverflowPolicy: EventOverflowCoalesce,
+	}, nil)
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	events := hc.Events()
+	cracked := make(chan CrackedPayload, 1)
+	go func() {
+		for e := range events {
+			if pl, ok := e.Payload.(CrackedPayload); ok {
+				cracked <- pl
+			}
+		}
+	}()
+
+	err = hc.RunJob("-O", "-a", "0", "-m", "0", "-D", DeviceType, "--session", "test9", "--potfile-disable", "5d41402abc4b2a76b9719d911017c592", "./testdata/test_dictionary.txt")
+	require.NoError(t, err)
+
+	select {
+	case pl := <-cracked:
+		require.Equal(t, "5d41402abc4b2a76b9719d911017c592", pl.Hash)
+		require.Equal(t, "hello", pl.Value)
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not receive a cracked event")
+	}
+}
+
+func TestGoCatStateChanges(t *testing.T) {
+	states := []State{}
+
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, func(hc unsafe.Pointer, payload interface{}) {
+		if pl, ok := payload.(StateChangePayload); ok {
+			states = append(states, pl.To)
+		}
+	})
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+	require.Equal(t, StateIdle, hc.State())
+
+	err = hc.RunJob("-O", "-a", "0", "-m", "0", "-D", DeviceType, "--session", "test10", "--potfile-disable", "5d41402abc4b2a76b9719d911017c592", "./testdata/test_dictionary.txt")
+	require.NoError(t, err)
+	require.Equal(t, StateFinished, hc.State())
+	require.Equal(t, StateInitializing, states[0])
+	require.Contains(t, states, StateRunning)
+	require.Equal(t, StateFinished, states[len(states)-1])
+}
+
+func TestGoCatKeyspace(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	keyspace, err := hc.Keyspace(hcargp.HashcatSessionOptions{
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+		HashType:                     hcargp.GetIntPtr(0),
+		InputFile:                    "5d41402abc4b2a76b9719d911017c592",
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), keyspace)
+}
+
+func TestGoCatBenchmark(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	results, err := hc.Benchmark([]int{0, 100}, hcargp.HashcatSessionOptions{
+		OpenCLDeviceTypes:      hcargp.GetStringPtr(DeviceType),
+		OptimizedKernelEnabled: hcargp.GetBoolPtr(true),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+
+	modes := map[int]bool{}
+	for _, res := range results {
+		modes[res.HashMode] = true
+		require.Greater(t, res.HashesPerSec, float64(0))
+		require.NotZero(t, res.DeviceID)
+	}
+	require.True(t, modes[0])
+	require.True(t, modes[100])
+}
+
 func TestGocatRussianHashes(t *testing.T) {
 	crackedHashes := map[string]*string{}
 
@@ -180,17 +331,89 @@ func TestGocatRussianHashes(t *testing.T) {
 		OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
 		SessionName:                  hcargp.GetStringPtr("test1"),
 		OptimizedKernelEnabled:       hcargp.GetBoolPtr(true),
-		AttackMode:                   hcargp.GetIntPtr(0),
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
 		HashType:                     hcargp.GetIntPtr(0),
 		PotfileDisable:               hcargp.GetBoolPtr(true),
 		InputFile:                    "./testdata/russian_test.hashes",
-		DictionaryMaskDirectoryInput: hcargp.GetStringPtr("./testdata/russian_test.dictionary"),
+		DictionaryMaskDirectoryInput: []string{"./testdata/russian_test.dictionary"},
 	})
 
 	require.NoError(t, err)
 	require.Len(t, crackedHashes, 4) // the previous run will still exist in this map
 }
 
+func TestGoCatRunJobContextCancelled(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath:        DefaultSharedPath,
+		CancelGracePeriod: 5 * time.Second,
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = hc.RunJobContext(ctx, "-a", "3", "-m", "0", "-D", DeviceType, "--session", "test7", "--potfile-disable", "9f9d51bc70ef21ca5c14f307980a29d2", "?a?a?a?a?a?a?a?a?a")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.True(t, errors.Is(err, ErrCancelledAtCheckpoint) || errors.Is(err, ErrCancelledAborted))
+}
+
+func TestGoCatRunJobContextAlreadyCancelled(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, emptyCallback)
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = hc.RunJobContext(ctx, "-a", "0", "-m", "0", "5d41402abc4b2a76b9719d911017c592", "./testdata/test_dictionary.txt")
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestWatchContextFinishedSession(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	close(done)
+
+	// a session that finished before ctx was noticed must never be reported (or stopped) as cancelled
+	hc := &Hashcat{}
+	for i := 0; i < 100; i++ {
+		cancelled := make(chan error, 1)
+		hc.watchContext(ctx, done, cancelled)
+		require.NoError(t, <-cancelled)
+	}
+}
+
+func TestGoCatTypedErrors(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, emptyCallback)
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	err = hc.RunJob("-a", "0", "-m", "0", "-D", DeviceType, "--potfile-disable", "5d41402abc4b2a76b9719d911017c592", "./testdata/does_not_exist.dictionary")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrFileNotFound))
+
+	err = hc.RunJob("-a", "0", "-m", "0", "-D", DeviceType, "--potfile-disable", "5d41402abc4b2a76b9", "./testdata/test_dictionary.txt")
+	require.Error(t, err)
+
+	var pe *HashParseError
+	require.True(t, errors.As(err, &pe))
+	require.True(t, errors.Is(err, ErrTokenLength))
+}
+
 func TestGoCatStopAtCheckpointWithNoRunningSession(t *testing.T) {
 	hc, err := New(Options{
 		SharedPath: DefaultSharedPath,
@@ -201,7 +424,25 @@ func TestGoCatStopAtCheckpointWithNoRunningSession(t *testing.T) {
 	require.NoError(t, err)
 
 	err = hc.StopAtCheckpoint()
-	require.Equal(t, ErrUnableToStopAtCheckpoint, err)
+	require.True(t, errors.Is(err, ErrUnableToStopAtCheckpoint))
+
+	var stateErr *StateError
+	require.True(t, errors.As(err, &stateErr))
+	require.Equal(t, StateIdle, stateErr.State)
+}
+
+func TestGoCatPauseResumeBypassWithNoRunningSession(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, emptyCallback)
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	require.True(t, errors.Is(hc.Pause(), ErrSessionNotRunning))
+	require.True(t, errors.Is(hc.Resume(), ErrSessionNotPaused))
+	require.True(t, errors.Is(hc.Bypass(), ErrSessionNotRunning))
 }
 
 func TestExampleHashcat_RunJobWithOptions(t *testing.T) {
@@ -243,12 +484,12 @@ func TestExampleHashcat_RunJobWithOptions(t *testing.T) {
 	err = hc.RunJobWithOptions(hcargp.HashcatSessionOptions{
 		OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
 		SessionName:                  hcargp.GetStringPtr("test2"),
-		AttackMode:                   hcargp.GetIntPtr(0),
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
 		HashType:                     hcargp.GetIntPtr(0),
 		PotfileDisable:               hcargp.GetBoolPtr(true),
 		OptimizedKernelEnabled:       hcargp.GetBoolPtr(true),
 		InputFile:                    "9f9d51bc70ef21ca5c14d307980a29d2",
-		DictionaryMaskDirectoryInput: hcargp.GetStringPtr("./testdata/test_dictionary.txt"),
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
 	})
 
 	if err != nil {
@@ -279,6 +520,365 @@ func TestHashIdentify(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestListDevices(t *testing.T) {
+	devices, err := ListDevices(Options{
+		SharedPath: DefaultSharedPath,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, devices)
+
+	for _, device := range devices {
+		t.Logf("Device #%d: %s (%s, %s, %s)", device.ID, device.Name, device.Platform, device.Type, device.Vendor)
+		require.NotZero(t, device.ID)
+		require.NotEmpty(t, device.Platform)
+	}
+}
+
+func TestGoCatShowAndLeft(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	cracked, err := hc.Show("./testdata/two_md5.hashes", 0, "./testdata/two_md5.potfile", false)
+	require.NoError(t, err)
+	require.Len(t, cracked, 2)
+	for _, pl := range cracked {
+		require.True(t, pl.IsPotfile)
+	}
+
+	left, err := hc.Left("./testdata/one_md5_in_potfile.hashes", 0, "./testdata/one_md5_in_potfile.potfile", false)
+	require.NoError(t, err)
+	require.Equal(t, []LeftPayload{{Hash: "6b34fe24ac2ff8103f6fce1f0da2ef57"}}, left)
+
+	hashfile := filepath.Join(t.TempDir(), "usernames.hashes")
+	require.NoError(t, os.WriteFile(hashfile, []byte("alice:5d41402abc4b2a76b9719d911017c592\nbob:6b34fe24ac2ff8103f6fce1f0da2ef57\n"), 0600))
+
+	cracked, err = hc.Show(hashfile, 0, "./testdata/one_md5_in_potfile.potfile", true)
+	require.NoError(t, err)
+	require.Len(t, cracked, 1)
+	require.Equal(t, "alice", cracked[0].Username)
+	require.Equal(t, "5d41402abc4b2a76b9719d911017c592", cracked[0].Hash)
+	require.Equal(t, "hello", cracked[0].Value)
+
+	left, err = hc.Left(hashfile, 0, "./testdata/one_md5_in_potfile.potfile", true)
+	require.NoError(t, err)
+	require.Equal(t, []LeftPayload{{Username: "bob", Hash: "6b34fe24ac2ff8103f6fce1f0da2ef57"}}, left)
+}
+
+func TestGoCatStatusInterval(t *testing.T) {
+	// the callback runs on hashcat's (or the monitor's) goroutine so results are checked once the session returns
+	var mu sync.Mutex
+	var statuses int
+	var incompleteStatus bool
+	var statusAfterFinal bool
+	var finished bool
+
+	hc, err := New(Options{
+		SharedPath:     DefaultSharedPath,
+		StatusInterval: 250 * time.Millisecond,
+	}, func(_ unsafe.Pointer, payload interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch pl := payload.(type) {
+		case StatusPayload:
+			incompleteStatus = incompleteStatus || pl.Status == nil || pl.Snapshot == nil
+			statuses++
+			statusAfterFinal = statusAfterFinal || finished
+		case FinalStatusPayload:
+			finished = true
+		}
+	})
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	err = hc.RunJobWithOptions(hcargp.HashcatSessionOptions{
+		OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackBruteForce),
+		HashType:                     hcargp.GetIntPtr(0),
+		PotfileDisable:               hcargp.GetBoolPtr(true),
+		DisableRestore:               hcargp.GetBoolPtr(true),
+		MaxRuntimeSeconds:            hcargp.GetIntPtr(3),
+		InputFile:                    "9f9d51bc70ef21ca5c14f307980a29d8",
+		DictionaryMaskDirectoryInput: []string{"?a?a?a?a?a?a?a?a"},
+	})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.True(t, finished)
+	require.NotZero(t, statuses)
+	require.False(t, incompleteStatus, "StatusPayload is missing its Status or Snapshot")
+	require.False(t, statusAfterFinal)
+}
+
+func TestGoCatRecoveryThreshold(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+		RecoveryThreshold: RecoveryThreshold{
+			Window:    2 * time.Second,
+			MinCracks: 1,
+		},
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	res, err := hc.Run(context.Background(), Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+			AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackBruteForce),
+			HashType:                     hcargp.GetIntPtr(0),
+			PotfileDisable:               hcargp.GetBoolPtr(true),
+			DisableRestore:               hcargp.GetBoolPtr(true),
+			MaxRuntimeSeconds:            hcargp.GetIntPtr(30),
+			InputFile:                    "9f9d51bc70ef21ca5c14f307980a29d8",
+			DictionaryMaskDirectoryInput: []string{"?a?a?a?a?a?a?a?a"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.True(t, res.RecoveryStalled)
+	require.Equal(t, OutcomeAborted, res.Outcome)
+	require.NotNil(t, res.Status)
+	require.Zero(t, res.Status.RecoveryRate.PerMinute)
+}
+
+func TestGoCatRecoveryThresholdWhilePaused(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+		RecoveryThreshold: RecoveryThreshold{
+			Window:    2 * time.Second,
+			MinCracks: 1,
+		},
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	type runResult struct {
+		res *JobResult
+		err error
+	}
+	done := make(chan runResult, 1)
+	go func() {
+		res, err := hc.Run(context.Background(), Job{
+			Options: &hcargp.HashcatSessionOptions{
+				OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+				AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackBruteForce),
+				HashType:                     hcargp.GetIntPtr(0),
+				PotfileDisable:               hcargp.GetBoolPtr(true),
+				DisableRestore:               hcargp.GetBoolPtr(true),
+				MaxRuntimeSeconds:            hcargp.GetIntPtr(60),
+				InputFile:                    "9f9d51bc70ef21ca5c14f307980a29d8",
+				DictionaryMaskDirectoryInput: []string{"?a?a?a?a?a?a?a?a"},
+			},
+		})
+		done <- runResult{res, err}
+	}()
+
+	require.Eventually(t, func() bool { return hc.State() == StateRunning }, 30*time.Second, 10*time.Millisecond)
+	require.NoError(t, hc.Pause())
+
+	// stay paused for longer than the window, the session must not be stopped
+	select {
+	case <-done:
+		t.Fatal("session stopped while paused")
+	case <-time.After(5 * time.Second):
+	}
+	require.Equal(t, StatePaused, hc.State())
+
+	hc.AbortRunningTask()
+	r := <-done
+	require.NoError(t, r.err)
+	require.NotNil(t, r.res)
+	require.False(t, r.res.RecoveryStalled)
+}
+
+func TestGoCatRunWithCandidates(t *testing.T) {
+	crackedHashes := map[string]*string{}
+
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(crackedHashes))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	res, err := hc.Run(context.Background(), Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes: hcargp.GetStringPtr(DeviceType),
+			AttackMode:        hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:          hcargp.GetIntPtr(0),
+			PotfileDisable:    hcargp.GetBoolPtr(true),
+			InputFile:         "./testdata/two_md5.hashes",
+		},
+		Candidates: strings.NewReader("nope\nhello\nworld\nbob\n"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Cracked)
+	require.Equal(t, "Pipe", res.Status.GuessBase)
+
+	// a second session with rules, fed from a channel, must not see anything left over from the first one
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, candidate := range []string{"HELLO", "WORLD"} {
+			ch <- candidate
+		}
+	}()
+
+	res, err = hc.Run(context.Background(), Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes: hcargp.GetStringPtr(DeviceType),
+			AttackMode:        hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:          hcargp.GetIntPtr(0),
+			PotfileDisable:    hcargp.GetBoolPtr(true),
+			RuleLeft:          hcargp.GetStringPtr("l"),
+			InputFile:         "./testdata/two_md5.hashes",
+		},
+		Candidates: CandidatesFromChan(ch),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Cracked)
+}
+
+func TestGoCatRunWithHashes(t *testing.T) {
+	var mu sync.Mutex
+	originals := map[string]string{}
+
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, func(_ unsafe.Pointer, payload interface{}) {
+		if pl, ok := payload.(CrackedPayload); ok {
+			mu.Lock()
+			originals[pl.Value] = pl.Original
+			mu.Unlock()
+		}
+	})
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	res, err := hc.Run(context.Background(), Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+			AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:                     hcargp.GetIntPtr(0),
+			PotfileDisable:               hcargp.GetBoolPtr(true),
+			DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+		},
+		Hashes: []string{"5D41402ABC4B2A76B9719D911017C592", "7d793037a0760186574b0282f2f435e7"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Cracked)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[string]string{
+		"hello": "5D41402ABC4B2A76B9719D911017C592",
+		"world": "7d793037a0760186574b0282f2f435e7",
+	}, originals)
+}
+
+func TestGoCatPotfileStore(t *testing.T) {
+	store := NewMemoryPotfileStore()
+	store.Add("5d41402abc4b2a76b9719d911017c592", "hello")
+
+	crackedHashes := map[string]*string{}
+
+	hc, err := New(Options{
+		SharedPath:   DefaultSharedPath,
+		PotfileStore: store,
+	}, callbackForTests(crackedHashes))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	job := Job{
+		Options: &hcargp.HashcatSessionOptions{
+			OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
+			AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+			HashType:                     hcargp.GetIntPtr(0),
+			DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+		},
+		Hashes: []string{"5d41402abc4b2a76b9719d911017c592", "7d793037a0760186574b0282f2f435e7"},
+	}
+
+	res, err := hc.Run(context.Background(), job)
+	require.NoError(t, err)
+	require.Equal(t, 1, res.PotfileHits)
+	require.Equal(t, 1, res.Cracked)
+	require.Len(t, crackedHashes, 2)
+
+	plain, ok, err := store.Lookup("7d793037a0760186574b0282f2f435e7")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "world", plain)
+
+	// everything is in the store now so hashcat doesn't need to run at all
+	res, err = hc.Run(context.Background(), job)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeCracked, res.Outcome)
+	require.Equal(t, 2, res.PotfileHits)
+	require.Zero(t, res.Cracked)
+	require.Nil(t, res.Status)
+}
+
+func TestGoCatGenerateCandidates(t *testing.T) {
+	hc, err := New(Options{
+		SharedPath: DefaultSharedPath,
+	}, callbackForTests(nil))
+	defer hc.Free()
+
+	require.NotNil(t, hc)
+	require.NoError(t, err)
+
+	buf := new(strings.Builder)
+	err = hc.GenerateCandidates(hcargp.HashcatSessionOptions{
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+	}, buf)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"hello", "world", "chris", "bob", "hashcat!"}, strings.Fields(buf.String()))
+
+	// rules files are stacked, every rule of the first file is combined with every rule of the second
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "first.rule"), []byte(":\nu\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "second.rule"), []byte("$1\n$2\n"), 0600))
+
+	buf.Reset()
+	err = hc.GenerateCandidates(hcargp.HashcatSessionOptions{
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
+		RulesFile:                    []string{filepath.Join(dir, "first.rule"), filepath.Join(dir, "second.rule")},
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
+	}, buf)
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(buf.String()), 20)
+	require.Contains(t, strings.Fields(buf.String()), "HELLO2")
+
+	buf.Reset()
+	err = hc.GenerateCandidates(hcargp.HashcatSessionOptions{
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackBruteForce),
+		Skip:                         hcargp.GetIntPtr(2),
+		Limit:                        hcargp.GetIntPtr(5),
+		DictionaryMaskDirectoryInput: []string{"?d"},
+	}, buf)
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(buf.String()), 3)
+}
+
 func TestGoCatHccapx(t *testing.T) {
 	crackedHashes := map[string]*string{}
 
@@ -294,12 +894,12 @@ func TestGoCatHccapx(t *testing.T) {
 		OpenCLDeviceTypes:            hcargp.GetStringPtr(DeviceType),
 		SessionName:                  hcargp.GetStringPtr("test6"),
 		OptimizedKernelEnabled:       hcargp.GetBoolPtr(true),
-		AttackMode:                   hcargp.GetIntPtr(0),
+		AttackMode:                   hcargp.GetAttackModePtr(hcargp.AttackStraight),
 		HashType:                     hcargp.GetIntPtr(2500),
 		PotfileDisable:               hcargp.GetBoolPtr(true),
 		EnableDeprecated:             hcargp.GetBoolPtr(true),
 		InputFile:                    "./testdata/hashcat.hccapx",
-		DictionaryMaskDirectoryInput: hcargp.GetStringPtr("./testdata/test_dictionary.txt"),
+		DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
 	})
 
 	fmt.Printf("crackedHashes: %v\n", crackedHashes)
@@ -321,9 +921,13 @@ func TestCustomCommandLine(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, errorOptions)
 
-	missingDashOptions, err := hcargp.ParseOptions("thisisatest --optimized-kernel-enable=true --custom-charset1=DEADBEEF")
+	positionalOptions, err := hcargp.ParseOptions("thisisatest --optimized-kernel-enable=true --custom-charset1=DEADBEEF")
+	require.NoError(t, err)
+	require.Equal(t, "thisisatest", positionalOptions.InputFile)
+
+	unterminatedOptions, err := hcargp.ParseOptions(`-O -1 "DEADBEEF`)
 	require.Error(t, err)
-	require.Nil(t, missingDashOptions)
+	require.Nil(t, unterminatedOptions)
 
 }
 
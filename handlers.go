@@ -47,12 +47,30 @@ type CrackedPayload struct {
 	Hash      string
 	Value     string
 	CrackedAt time.Time
+	// Username is set for potfile results (--show) when the hashes were loaded with --username
+	Username string `json:",omitempty"`
+	// Original is the entry of Job.Hashes that Hash came from. hashcat may report a hash in a different form than it was given
+	Original string `json:",omitempty"`
+}
+
+// LeftPayload defines the structure of an uncracked hash from hashcat (--left) and sent to the user via the callback
+type LeftPayload struct {
+	Hash string
+	// Username is set when the hashes were loaded with --username
+	Username string `json:",omitempty"`
+}
+
+// StatusPayload is sent every Options.StatusInterval while hashcat is cracking
+type StatusPayload struct {
+	Status   *Status
+	Snapshot *StatusSnapshot
 }
 
 // FinalStatusPayload is returned at the end of the cracking session
 type FinalStatusPayload struct {
-	Status  *Status
-	EndedAt time.Time
+	Status   *Status
+	Snapshot *StatusSnapshot
+	EndedAt  time.Time
 	// AllHashesCracked is set when all hashes either exist in a potfile or are considered "weak"
 	AllHashesCracked bool
 }
@@ -123,6 +141,14 @@ func logMessageFromBuffer(buf unsafe.Pointer, lvl LogLevel) LogPayload {
 	}
 }
 
+// withParsedError sets pl.Error to the typed error described by the message, if it's recognized
+func withParsedError(pl LogPayload) LogPayload {
+	if err := parseHashcatMessage(pl.Message); err != nil {
+		pl.Error = err
+	}
+	return pl
+}
+
 func logMessageWithError(id uint32, err error) LogPayload {
 	return LogPayload{
 		Level:   ErrorMessage,
@@ -141,6 +167,54 @@ func logHashcatAction(id uint32, msg string) ActionPayload {
 	}
 }
 
+// separatorFromCtx returns the separator used by the session
+func separatorFromCtx(hcCtx *C.hashcat_ctx_t) string {
+	if hcCtx != nil && hcCtx.user_options != nil && hcCtx.user_options.separator != nil {
+		return C.GoString(hcCtx.user_options.separator)
+	}
+	return ":"
+}
+
+func hasUsername(hcCtx *C.hashcat_ctx_t) bool {
+	return hcCtx != nil && hcCtx.user_options != nil && bool(hcCtx.user_options.username)
+}
+
+// crackedPasswordFromEvent parses the buffer of an EVENT_CRACKER_HASH_CRACKED or EVENT_POTFILE_HASH_SHOW event
+func crackedPasswordFromEvent(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) (CrackedPayload, error) {
+	sepr := separatorFromCtx(hcCtx)
+	msg := C.GoString((*C.char)(buf))
+
+	// --show prefixes every result with its username
+	var username string
+	if id == C.EVENT_POTFILE_HASH_SHOW && hasUsername(hcCtx) {
+		username, msg = splitUsername(msg, sepr)
+	}
+
+	pl, err := getCrackedPassword(id, msg, sepr)
+	pl.Username = username
+	return pl, err
+}
+
+// leftHashFromEvent parses the buffer of an EVENT_POTFILE_HASH_LEFT event
+func leftHashFromEvent(hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) LeftPayload {
+	pl := LeftPayload{
+		Hash: C.GoString((*C.char)(buf)),
+	}
+
+	if hasUsername(hcCtx) {
+		pl.Username, pl.Hash = splitUsername(pl.Hash, separatorFromCtx(hcCtx))
+	}
+	return pl
+}
+
+// splitUsername splits the username off the front of msg
+func splitUsername(msg string, sep string) (username, rest string) {
+	if idx := strings.Index(msg, sep); idx != -1 {
+		return msg[:idx], msg[idx+len(sep):]
+	}
+	return "", msg
+}
+
 func getCrackedPassword(id uint32, msg string, sep string) (pl CrackedPayload, err error) {
 	// Some messages can have multiple variations of the separator (example: kerberos 13100)
 	// so we find the last one and use that to separate the original hash and it's value
@@ -53,3 +53,13 @@ func TestGetCrackedPassword(t *testing.T) {
 	assert.NotNil(t, ecperr)
 	assert.Equal(t, "Could not locate separator `;` in msg", err.Error())
 }
+
+func TestSplitUsername(t *testing.T) {
+	username, rest := splitUsername("bob:deadbeefdeadbeefdeadbeefdeadbeef:chris", ":")
+	assert.Equal(t, "bob", username)
+	assert.Equal(t, "deadbeefdeadbeefdeadbeefdeadbeef:chris", rest)
+
+	username, rest = splitUsername("deadbeefdeadbeefdeadbeefdeadbeef", ":")
+	assert.Equal(t, "", username)
+	assert.Equal(t, "deadbeefdeadbeefdeadbeefdeadbeef", rest)
+}
@@ -0,0 +1,95 @@
+package gocat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrHashesWithInputFile is raised when Job.Hashes is used alongside HashcatSessionOptions.InputFile
+	ErrHashesWithInputFile = errors.New("Job.Hashes cannot be used with InputFile")
+	// ErrHashesRequireOptions is raised when Job.Hashes is used without Job.Options
+	ErrHashesRequireOptions = errors.New("Job.Hashes requires Job.Options")
+)
+
+// writeHashesFile writes hashes into a file that is only readable by the current user inside of a private temporary directory.
+// cleanup removes both and is always safe to call, even when err is set
+func writeHashesFile(hashes []string) (path string, cleanup func(), err error) {
+	cleanup = func() {}
+
+	dir, err := os.MkdirTemp("", "gocat-hashes-")
+	if err != nil {
+		return "", cleanup, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	path = filepath.Join(dir, "hashes")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", cleanup, err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for i, hash := range hashes {
+		if strings.ContainsAny(hash, "\r\n") {
+			return "", cleanup, fmt.Errorf("hash %d contains a newline", i)
+		}
+
+		if _, err = w.WriteString(hash + "\n"); err != nil {
+			return "", cleanup, err
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return "", cleanup, err
+	}
+	return path, cleanup, f.Close()
+}
+
+// originalHashes maps the hashes reported by hashcat back to the entries of Job.Hashes.
+// hashcat can normalize a hash when it loads it (most commonly by lowercasing hex) so lookups fall back to a case-insensitive match
+type originalHashes struct {
+	exact  map[string]string
+	folded map[string]string
+}
+
+// newOriginalHashes indexes hashes. When usernames is set (--username), entries are also indexed without their username
+// as hashcat doesn't include it when reporting a crack
+func newOriginalHashes(hashes []string, sep string, usernames bool) *originalHashes {
+	o := &originalHashes{
+		exact:  make(map[string]string, len(hashes)),
+		folded: make(map[string]string, len(hashes)),
+	}
+
+	for _, original := range hashes {
+		keys := []string{original}
+		if usernames {
+			_, hash := splitUsername(original, sep)
+			keys = append(keys, hash)
+		}
+
+		for _, key := range keys {
+			if _, ok := o.exact[key]; !ok {
+				o.exact[key] = original
+			}
+
+			if _, ok := o.folded[strings.ToLower(key)]; !ok {
+				o.folded[strings.ToLower(key)] = original
+			}
+		}
+	}
+	return o
+}
+
+// lookup returns the entry of Job.Hashes that hash came from or an empty string if it can't be found
+func (o *originalHashes) lookup(hash string) string {
+	if original, ok := o.exact[hash]; ok {
+		return original
+	}
+	return o.folded[strings.ToLower(hash)]
+}
@@ -0,0 +1,73 @@
+package gocat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHashesFile(t *testing.T) {
+	path, cleanup, err := writeHashesFile([]string{"5d41402abc4b2a76b9719d911017c592", "7d793037a0760186574b0282f2f435e7"})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	fi, err = os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), fi.Mode().Perm())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592\n7d793037a0760186574b0282f2f435e7\n", string(b))
+
+	cleanup()
+	_, err = os.Stat(filepath.Dir(path))
+	assert.True(t, os.IsNotExist(err))
+
+	path, cleanup, err = writeHashesFile([]string{"hash\nwith a newline"})
+	assert.Error(t, err)
+	cleanup()
+	if path != "" {
+		_, err = os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestOriginalHashes(t *testing.T) {
+	o := newOriginalHashes([]string{"5D41402ABC4B2A76B9719D911017C592", "bob:7d793037a0760186574b0282f2f435e7"}, ":", true)
+
+	assert.Equal(t, "5D41402ABC4B2A76B9719D911017C592", o.lookup("5d41402abc4b2a76b9719d911017c592"))
+	assert.Equal(t, "bob:7d793037a0760186574b0282f2f435e7", o.lookup("7d793037a0760186574b0282f2f435e7"))
+	assert.Equal(t, "", o.lookup("9f9d51bc70ef21ca5c14f307980a29d8"))
+}
+
+func TestJobPrepareWithHashes(t *testing.T) {
+	_, cleanup, err := Job{Hashes: []string{"hash"}}.prepare()
+	cleanup()
+	assert.Equal(t, ErrHashesRequireOptions, err)
+
+	_, cleanup, err = Job{
+		Hashes:  []string{"hash"},
+		Options: &hcargp.HashcatSessionOptions{InputFile: "hash"},
+	}.prepare()
+	cleanup()
+	assert.Equal(t, ErrHashesWithInputFile, err)
+
+	args, cleanup, err := Job{
+		Hashes:  []string{"hash"},
+		Options: &hcargp.HashcatSessionOptions{HashType: hcargp.GetIntPtr(0)},
+	}.prepare()
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	assert.FileExists(t, args[1])
+
+	cleanup()
+	_, err = os.Stat(args[1])
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,169 @@
+package hcargp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AttackMode is the value of --attack-mode
+type AttackMode int
+
+// Attack modes supported by hashcat
+const (
+	AttackStraight       AttackMode = 0
+	AttackCombinator     AttackMode = 1
+	AttackBruteForce     AttackMode = 3
+	AttackHybridDictMask AttackMode = 6
+	AttackHybridMaskDict AttackMode = 7
+	AttackAssociation    AttackMode = 9
+)
+
+var attackModeNames = map[int]string{
+	int(AttackStraight):       "Straight",
+	int(AttackCombinator):     "Combination",
+	int(AttackBruteForce):     "Brute-force",
+	int(AttackHybridDictMask): "Hybrid Wordlist + Mask",
+	int(AttackHybridMaskDict): "Hybrid Mask + Wordlist",
+	int(AttackAssociation):    "Association",
+}
+
+func (m AttackMode) String() string {
+	return enumString(attackModeNames, int(m))
+}
+
+// ParseAttackMode parses an attack mode from its number ("3") or name ("Brute-force"), the name is case insensitive
+func ParseAttackMode(s string) (AttackMode, error) {
+	v, err := parseEnum(attackModeNames, "attack mode", s)
+	return AttackMode(v), err
+}
+
+// GetAttackModePtr returns the pointer of m
+func GetAttackModePtr(m AttackMode) *AttackMode {
+	return &m
+}
+
+// WorkloadProfile is the value of --workload-profile
+type WorkloadProfile int
+
+// Workload profiles supported by hashcat
+const (
+	WorkloadLow       WorkloadProfile = 1
+	WorkloadDefault   WorkloadProfile = 2
+	WorkloadHigh      WorkloadProfile = 3
+	WorkloadNightmare WorkloadProfile = 4
+)
+
+var workloadProfileNames = map[int]string{
+	int(WorkloadLow):       "Low",
+	int(WorkloadDefault):   "Default",
+	int(WorkloadHigh):      "High",
+	int(WorkloadNightmare): "Nightmare",
+}
+
+func (p WorkloadProfile) String() string {
+	return enumString(workloadProfileNames, int(p))
+}
+
+// ParseWorkloadProfile parses a workload profile from its number ("3") or name ("High"), the name is case insensitive
+func ParseWorkloadProfile(s string) (WorkloadProfile, error) {
+	v, err := parseEnum(workloadProfileNames, "workload profile", s)
+	return WorkloadProfile(v), err
+}
+
+// GetWorkloadProfilePtr returns the pointer of p
+func GetWorkloadProfilePtr(p WorkloadProfile) *WorkloadProfile {
+	return &p
+}
+
+// OutfileFormat is one of the values of --outfile-format
+type OutfileFormat int
+
+// Outfile formats supported by hashcat
+const (
+	OutfileHash              OutfileFormat = 1
+	OutfilePlain             OutfileFormat = 2
+	OutfileHexPlain          OutfileFormat = 3
+	OutfileCrackPos          OutfileFormat = 4
+	OutfileTimestampAbsolute OutfileFormat = 5
+	OutfileTimestampRelative OutfileFormat = 6
+)
+
+var outfileFormatNames = map[int]string{
+	int(OutfileHash):              "hash[:salt]",
+	int(OutfilePlain):             "plain",
+	int(OutfileHexPlain):          "hex_plain",
+	int(OutfileCrackPos):          "crack_pos",
+	int(OutfileTimestampAbsolute): "timestamp absolute",
+	int(OutfileTimestampRelative): "timestamp relative",
+}
+
+func (f OutfileFormat) String() string {
+	return enumString(outfileFormatNames, int(f))
+}
+
+// ParseOutfileFormat parses an outfile format from its number ("2") or name ("plain"), the name is case insensitive
+func ParseOutfileFormat(s string) (OutfileFormat, error) {
+	v, err := parseEnum(outfileFormatNames, "outfile format", s)
+	return OutfileFormat(v), err
+}
+
+// DebugMode is the value of --debug-mode
+type DebugMode int
+
+// Debug modes supported by hashcat
+const (
+	DebugFindingRule                             DebugMode = 1
+	DebugOriginalWord                            DebugMode = 2
+	DebugOriginalWordFindingRule                 DebugMode = 3
+	DebugOriginalWordFindingRuleProcessedWord    DebugMode = 4
+	DebugOriginalWordFindingRuleProcessedWordDir DebugMode = 5
+)
+
+var debugModeNames = map[int]string{
+	int(DebugFindingRule):                             "Finding-Rule",
+	int(DebugOriginalWord):                            "Original-Word",
+	int(DebugOriginalWordFindingRule):                 "Original-Word:Finding-Rule",
+	int(DebugOriginalWordFindingRuleProcessedWord):    "Original-Word:Finding-Rule:Processed-Word",
+	int(DebugOriginalWordFindingRuleProcessedWordDir): "Original-Word:Finding-Rule:Processed-Word:Wordlist",
+}
+
+func (m DebugMode) String() string {
+	return enumString(debugModeNames, int(m))
+}
+
+// ParseDebugMode parses a debug mode from its number ("1") or name ("Finding-Rule"), the name is case insensitive
+func ParseDebugMode(s string) (DebugMode, error) {
+	v, err := parseEnum(debugModeNames, "debug mode", s)
+	return DebugMode(v), err
+}
+
+// GetDebugModePtr returns the pointer of m
+func GetDebugModePtr(m DebugMode) *DebugMode {
+	return &m
+}
+
+// enumString returns the name of v, or its number if hashcat doesn't document it
+func enumString(names map[int]string, v int) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return strconv.Itoa(v)
+}
+
+func parseEnum(names map[int]string, kind, s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := names[n]; ok {
+			return n, nil
+		}
+		return 0, fmt.Errorf("invalid %s %d", kind, n)
+	}
+
+	for v, name := range names {
+		if strings.EqualFold(name, s) {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s %q", kind, s)
+}
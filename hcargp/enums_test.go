@@ -0,0 +1,67 @@
+package hcargp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttackMode(t *testing.T) {
+	assert.Equal(t, "Brute-force", AttackBruteForce.String())
+	assert.Equal(t, "2", AttackMode(2).String())
+
+	for _, s := range []string{"3", "brute-force", " Brute-force "} {
+		mode, err := ParseAttackMode(s)
+		require.NoError(t, err)
+		assert.Equal(t, AttackBruteForce, mode)
+	}
+
+	_, err := ParseAttackMode("2")
+	assert.EqualError(t, err, "invalid attack mode 2")
+
+	_, err = ParseAttackMode("dictionary")
+	assert.EqualError(t, err, `invalid attack mode "dictionary"`)
+}
+
+func TestWorkloadProfile(t *testing.T) {
+	assert.Equal(t, "Nightmare", WorkloadNightmare.String())
+
+	profile, err := ParseWorkloadProfile("high")
+	require.NoError(t, err)
+	assert.Equal(t, WorkloadHigh, profile)
+
+	_, err = ParseWorkloadProfile("5")
+	assert.Error(t, err)
+}
+
+func TestOutfileFormat(t *testing.T) {
+	assert.Equal(t, "hex_plain", OutfileHexPlain.String())
+
+	format, err := ParseOutfileFormat("timestamp relative")
+	require.NoError(t, err)
+	assert.Equal(t, OutfileTimestampRelative, format)
+
+	opts := HashcatSessionOptions{OutfileFormat: []OutfileFormat{OutfileHash, OutfilePlain}}
+	args, err := opts.MarshalArgs()
+	require.NoError(t, err)
+	assert.Contains(t, args, "--outfile-format=1,2")
+
+	parsed, err := ParseOptions("--outfile-format=1,2 hashes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, opts.OutfileFormat, parsed.OutfileFormat)
+}
+
+func TestDebugMode(t *testing.T) {
+	assert.Equal(t, "Original-Word:Finding-Rule", DebugOriginalWordFindingRule.String())
+
+	mode, err := ParseDebugMode("finding-rule")
+	require.NoError(t, err)
+	assert.Equal(t, DebugFindingRule, mode)
+
+	opts, err := ParseOptions("-a 0 -w 3 --debug-mode=4 hashes.txt words.txt")
+	require.NoError(t, err)
+	assert.Equal(t, AttackStraight, *opts.AttackMode)
+	assert.Equal(t, WorkloadHigh, *opts.WorkloadProfile)
+	assert.Equal(t, DebugOriginalWordFindingRuleProcessedWord, *opts.DebugMode)
+}
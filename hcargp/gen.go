@@ -0,0 +1,3 @@
+package hcargp
+
+//go:generate go run generator.go
@@ -0,0 +1,365 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// outputFile is the generated file. Its current version is read to find the options that were removed from hashcat
+const outputFile = "./hashcat_options.go"
+
+var (
+	// usage.c:  " -m, --hash-type                | Num  | Hash-type, references below (otherwise autodetect)         | -m 1000",
+	rxpUsageOption = regexp.MustCompile(`^\s*"\s*(?:-([A-Za-z0-9]),)?\s*--([a-z0-9-]+)\s*\|\s*([A-Za-z]*)\s*\|\s*(.*?)\s*\|\s*(.*?)\s*",?\s*$`)
+	// user_options.c:  {"hash-type",                 required_argument, NULL, IDX_HASH_MODE},
+	rxpLongOption = regexp.MustCompile(`\{\s*"([a-z0-9-]+)"\s*,\s*(no_argument|required_argument|optional_argument)`)
+)
+
+// skipped are options that don't belong in a cracking session, either because they only affect hashcat's terminal
+// or because gocat exposes them through its own API
+var skipped = map[string]string{
+	"version":            "",
+	"help":               "",
+	"quiet":              "",
+	"status":             "",
+	"status-json":        "",
+	"status-timer":       "",
+	"machine-readable":   "",
+	"stdout":             "see gocat's Hashcat.GenerateCandidates",
+	"show":               "see gocat's Hashcat.Show",
+	"left":               "see gocat's Hashcat.Left",
+	"benchmark":          "see gocat's Hashcat.Benchmark",
+	"benchmark-all":      "see gocat's Hashcat.Benchmark",
+	"benchmark-min":      "see gocat's Hashcat.Benchmark",
+	"benchmark-max":      "see gocat's Hashcat.Benchmark",
+	"speed-only":         "",
+	"progress-only":      "",
+	"backend-info":       "see gocat's ListDevices",
+	"keyspace":           "see gocat's Hashcat.Keyspace",
+	"total-candidates":   "see gocat's Hashcat.Keyspace",
+	"hash-info":          "see the types package",
+	"example-hashes":     "see the types package",
+	"brain-server":       "runs a brain server rather than a session",
+	"brain-server-timer": "runs a brain server rather than a session",
+}
+
+// fieldNames keeps the names of fields that were written by hand before the struct was generated
+var fieldNames = map[string]string{
+	"hex-charset":              "IsHexCharset",
+	"hex-salt":                 "IsHexSalt",
+	"hex-wordlist":             "IsHexWordlist",
+	"markov-disable":           "DisableMarkov",
+	"markov-classic":           "EnableClassicMarkov",
+	"runtime":                  "MaxRuntimeSeconds",
+	"session":                  "SessionName",
+	"restore":                  "RestoreSession",
+	"restore-disable":          "DisableRestore",
+	"outfile":                  "OutfilePath",
+	"outfile-autohex-disable":  "OutfileDisableAutoHex",
+	"username":                 "IgnoreUsername",
+	"remove":                   "RemoveCrackedHash",
+	"remove-timer":             "RemoveCrackedHashTimer",
+	"truecrypt-keyfiles":       "TrueCryptKeyFiles",
+	"veracrypt-keyfiles":       "VeraCryptKeyFiles",
+	"optimized-kernel-enable":  "OptimizedKernelEnabled",
+	"hwmon-disable":            "HWMonitorDisable",
+	"hwmon-temp-abort":         "HWMonitorTempAbort",
+	"increment":                "IncrementMask",
+	"increment-min":            "IncrementMaskMin",
+	"increment-max":            "IncrementMaskMax",
+	"deprecated-check-disable": "EnableDeprecated",
+}
+
+// existingOption is a field of the HashcatSessionOptions struct that was generated last time
+type existingOption struct {
+	Flag  string
+	Field string
+	Type  string
+	// Deprecated is set if the option had already been removed from hashcat
+	Deprecated bool
+}
+
+// acronyms are words that are not simply title cased in field names
+var acronyms = map[string]string{
+	"cpu":       "CPU",
+	"cuda":      "CUDA",
+	"hip":       "HIP",
+	"opencl":    "OpenCL",
+	"json":      "JSON",
+	"hcstat2":   "HCStat2",
+	"tmto":      "TMTO",
+	"pim":       "PIM",
+	"truecrypt": "TrueCrypt",
+	"veracrypt": "VeraCrypt",
+	"hccapx":    "Hccapx",
+}
+
+// goTypes maps the type column of usage.c to a go type
+var goTypes = map[string]string{
+	"":     "*bool",
+	"Num":  "*int",
+	"Port": "*int",
+	"Str":  "*string",
+	"File": "*string",
+	"Dir":  "*string",
+	"Char": "*string",
+	"Code": "*string",
+	"Rule": "*string",
+	"CS":   "*string",
+	"Hex":  "*string",
+}
+
+// enumOptions are options whose values are one of hashcat's documented modes, see enums.go
+var enumOptions = map[string]string{
+	"attack-mode":      "*AttackMode",
+	"workload-profile": "*WorkloadProfile",
+	"debug-mode":       "*DebugMode",
+}
+
+// sliceOptions are options that accept several values. They're either repeated on the command line (-r a.rule -r b.rule)
+// or, when tagOptions contains comma, passed once with their values separated by commas
+var sliceOptions = map[string]struct {
+	Type       string
+	TagOptions string
+}{
+	"rules-file":         {Type: "[]string", TagOptions: ",omitempty"},
+	"truecrypt-keyfiles": {Type: "[]string", TagOptions: ",omitempty,comma"},
+	"veracrypt-keyfiles": {Type: "[]string", TagOptions: ",omitempty,comma"},
+	"outfile-format":     {Type: "[]OutfileFormat", TagOptions: ",omitempty,comma"},
+}
+
+type option struct {
+	Short       string
+	Long        string
+	Type        string
+	Description string
+	Example     string
+}
+
+func fieldName(long string) string {
+	if name, ok := fieldNames[long]; ok {
+		return name
+	}
+
+	var b strings.Builder
+	for _, word := range strings.Split(long, "-") {
+		if acronym, ok := acronyms[word]; ok {
+			b.WriteString(acronym)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func parseUsage(buff []byte) []option {
+	var options []option
+	for _, line := range strings.Split(string(buff), "\n") {
+		matches := rxpUsageOption.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		options = append(options, option{
+			Short:       matches[1],
+			Long:        matches[2],
+			Type:        matches[3],
+			Description: matches[4],
+			Example:     matches[5],
+		})
+	}
+	return options
+}
+
+func parseLongOptions(buff []byte) map[string]bool {
+	longOptions := make(map[string]bool)
+	for _, matches := range rxpLongOption.FindAllSubmatch(buff, -1) {
+		longOptions[string(matches[1])] = true
+	}
+	return longOptions
+}
+
+// parseExistingOptions returns the options of the HashcatSessionOptions struct in path, in the order they're declared.
+// Fields without a flag (the positional inputs) are left out
+func parseExistingOptions(path string) ([]existingOption, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var options []existingOption
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "HashcatSessionOptions" {
+			return true
+		}
+
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		for _, field := range st.Fields.List {
+			if field.Tag == nil || len(field.Names) != 1 {
+				continue
+			}
+
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+
+			flag, _, _ := strings.Cut(reflect.StructTag(tag).Get("hashcat"), ",")
+			if !strings.HasPrefix(flag, "--") {
+				continue
+			}
+
+			options = append(options, existingOption{
+				Flag:       strings.TrimPrefix(flag, "--"),
+				Field:      field.Names[0].Name,
+				Type:       types.ExprString(field.Type),
+				Deprecated: field.Doc != nil && strings.Contains(field.Doc.Text(), "Deprecated:"),
+			})
+		}
+		return false
+	})
+	return options, nil
+}
+
+func main() {
+	srcPath := os.Getenv("HASHCAT_SRC_PATH")
+	if srcPath == "" {
+		log.Fatal("HASHCAT_SRC_PATH must be set to the root of hashcat's source tree to generate code")
+	}
+
+	usage, err := ioutil.ReadFile(filepath.Join(srcPath, "src", "usage.c"))
+	if err != nil {
+		log.Fatalf("Could not read usage.c: %s", err)
+	}
+
+	userOptions, err := ioutil.ReadFile(filepath.Join(srcPath, "src", "user_options.c"))
+	if err != nil {
+		log.Fatalf("Could not read user_options.c: %s", err)
+	}
+
+	options := parseUsage(usage)
+	if len(options) == 0 {
+		log.Fatal("Could not locate any options in usage.c")
+	}
+
+	longOptions := parseLongOptions(userOptions)
+	generated := make(map[string]bool, len(options))
+
+	existing, err := parseExistingOptions(outputFile)
+	if err != nil {
+		log.Fatalf("Could not parse %s: %s", outputFile, err)
+	}
+
+	b := new(bytes.Buffer)
+	b.WriteString("// Code automatically generated; DO NOT EDIT.\n")
+	b.WriteString("\n")
+	b.WriteString("package hcargp\n")
+	b.WriteString("\n")
+	b.WriteString("// HashcatSessionOptions represents all the available hashcat options. The values here should always follow the latest version of hashcat\n")
+	b.WriteString("type HashcatSessionOptions struct {\n")
+
+	for _, opt := range options {
+		if !longOptions[opt.Long] {
+			log.Printf("WRN: --%s is documented in usage.c but missing from user_options.c", opt.Long)
+			continue
+		}
+
+		if _, ok := skipped[opt.Long]; ok {
+			continue
+		}
+
+		goType, ok := goTypes[opt.Type]
+		if !ok {
+			log.Fatalf("Unknown type %q for --%s", opt.Type, opt.Long)
+		}
+
+		if enumType, ok := enumOptions[opt.Long]; ok {
+			goType = enumType
+		}
+
+		tagOptions := ",omitempty"
+		if slice, ok := sliceOptions[opt.Long]; ok {
+			goType, tagOptions = slice.Type, slice.TagOptions
+		}
+
+		flags := "--" + opt.Long
+		tag := fmt.Sprintf(`hashcat:"--%s%s"`, opt.Long, tagOptions)
+		if opt.Short != "" {
+			flags = "-" + opt.Short + ", " + flags
+			tag += fmt.Sprintf(` short:"-%s"`, opt.Short)
+		}
+
+		generated[opt.Long] = true
+		b.WriteString(fmt.Sprintf("\t// %s (%s): %s\n", fieldName(opt.Long), flags, opt.Description))
+		b.WriteString(fmt.Sprintf("\t%s %s `%s`\n", fieldName(opt.Long), goType, tag))
+	}
+
+	// fields of the previous struct that weren't generated again have been removed from hashcat. They're kept
+	// (and deprecated) so existing code still compiles
+	for _, opt := range existing {
+		if generated[opt.Flag] {
+			if opt.Deprecated {
+				log.Printf("WRN: --%s (%s) is supported by hashcat again", opt.Flag, opt.Field)
+			}
+			continue
+		}
+
+		if _, ok := skipped[opt.Flag]; ok {
+			log.Printf("WRN: --%s (%s) is now skipped and has been deprecated", opt.Flag, opt.Field)
+		} else if !opt.Deprecated {
+			log.Printf("WRN: --%s (%s) has been removed from hashcat", opt.Flag, opt.Field)
+		}
+
+		b.WriteString(fmt.Sprintf("\t// %s (--%s) is no longer supported by hashcat.\n", opt.Field, opt.Flag))
+		b.WriteString("\t//\n")
+		b.WriteString(fmt.Sprintf("\t// Deprecated: --%s has been removed from hashcat and will be rejected.\n", opt.Flag))
+		b.WriteString(fmt.Sprintf("\t%s %s `hashcat:\"--%s,omitempty\"`\n", opt.Field, opt.Type, opt.Flag))
+	}
+
+	b.WriteString("\n")
+	b.WriteString("\t// InputFile can be a single hash or multiple hashes via a hashfile or hccapx\n")
+	b.WriteString("\tInputFile string `hashcat:\",\"`\n")
+	b.WriteString("\t// DictionaryMaskDirectoryInput are the dictionaries, masks, or directories used by the attack mode\n")
+	b.WriteString("\tDictionaryMaskDirectoryInput []string `hashcat:\",omitempty\"`\n")
+	b.WriteString("}\n")
+
+	formattedSource, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("Could not format generated source: %s", err)
+	}
+
+	fd, err := os.Create(outputFile)
+	if err != nil {
+		log.Fatalf("Could not create destination file: %s", err)
+	}
+	defer fd.Close()
+
+	if _, err := fd.Write(formattedSource); err != nil {
+		log.Fatalf("Could not write destination file: %s", err)
+	}
+}
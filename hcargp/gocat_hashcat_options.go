@@ -1,11 +1,10 @@
 package hcargp
 
 import (
-	"flag"
 	"fmt"
-	"io"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -24,196 +23,58 @@ func GetBoolPtr(b bool) *bool {
 	return &b
 }
 
-/*
-We skip the following arguments because they are not needed:
-- version
-- help
-- quiet
-- status
-- status-timer
-- machine-readable
-- stdout
-- show
-- left
-- benchmark
-- speed-only (todo?)
-- progress-only (todo?)
-- opencl-info
-- keyspace
-*/
-
-// HashcatSessionOptions represents all the available hashcat options. The values here should always follow the latest version of hashcat
-type HashcatSessionOptions struct {
-	HashType               *int    `hashcat:"--hash-type,omitempty"`
-	AttackMode             *int    `hashcat:"--attack-mode,omitempty"`
-	IsHexCharset           *bool   `hashcat:"--hex-charset,omitempty"`
-	IsHexSalt              *bool   `hashcat:"--hex-salt,omitempty"`
-	IsHexWordlist          *bool   `hashcat:"--hex-wordlist,omitempty"`
-	KeepGuessing           *bool   `hashcat:"--keep-guessing,omitempty"`
-	Loopback               *bool   `hashcat:"--loopback,omitempty"`
-	WeakHashThreshold      *int    `hashcat:"--weak-hash-threshold,omitempty"`
-	MarkovHCStat           *string `hashcat:"--markov-hcstat,omitempty"`
-	DisableMarkov          *bool   `hashcat:"--markov-disable,omitempty"`
-	EnableClassicMarkov    *bool   `hashcat:"--markov-classic,omitempty"`
-	MarkovThreshold        *int    `hashcat:"--markov-threshold,omitempty"`
-	Force                  *bool   `hashcat:"--force,omitempty"`
-	MaxRuntimeSeconds      *int    `hashcat:"--runtime,omitempty"`
-	SessionName            *string `hashcat:"--session,omitempty"`
-	RestoreSession         *bool   `hashcat:"--restore,omitempty"`
-	DisableRestore         *bool   `hashcat:"--restore-disable,omitempty"`
-	RestoreFilePath        *string `hashcat:"--restore-file-path,omitempty"`
-	OutfilePath            *string `hashcat:"--outfile,omitempty"`
-	OutfileFormat          *int    `hashcat:"--outfile-format,omitempty"`
-	OutfileDisableAutoHex  *bool   `hashcat:"--outfile-autohex-disable,omitempty"`
-	OutfileCheckTimer      *int    `hashcat:"--outfile-check-timer,omitempty"`
-	Separator              *string `hashcat:"--separator,omitempty"`
-	IgnoreUsername         *bool   `hashcat:"--username,omitempty"`
-	RemoveCrackedHash      *bool   `hashcat:"--remove,omitempty"`
-	RemoveCrackedHashTimer *int    `hashcat:"--remove-timer,omitempty"`
-	PotfileDisable         *bool   `hashcat:"--potfile-disable,omitempty"`
-	PotfilePath            *string `hashcat:"--potfile-path,omitempty"`
-	EncodingFrom           *string `hashcat:"--encoding-from,omitempty"`
-	EncodingTo             *string `hashcat:"--encoding-to,omitempty"`
-	DebugMode              *int    `hashcat:"--debug-mode,omitempty"`
-	DebugFile              *string `hashcat:"--debug-file,omitempty"`
-	InductionDir           *string `hashcat:"--induction-dir,omitempty"`
-	LogfileDisable         *bool   `hashcat:"--logfile-disable,omitempty"`
-	HccapxMessagePair      *string `hashcat:"--hccapx-message-pair,omitempty"`
-	NonceErrorCorrections  *int    `hashcat:"--nonce-error-corrections,omitempty"`
-	TrueCryptKeyFiles      *string `hashcat:"--truecrypt-keyfiles,omitempty"`
-	VeraCryptKeyFiles      *string `hashcat:"--veracrypt-keyfiles,omitempty"`
-	VeraCryptPIM           *int    `hashcat:"--veracrypt-pim,omitempty"`
-	VeraCryptPIMStart      *int    `hashcat:"--veracrypt-pim-start,omitempty"`
-	VeraCryptPIMStop       *int    `hashcat:"--veracrypt-pim-stop,omitempty"`
-	SegmentSize            *int    `hashcat:"--segment-size,omitempty"`
-	BitmapMin              *int    `hashcat:"--bitmap-min,omitempty"`
-	BitmapMax              *int    `hashcat:"--bitmap-max,omitempty"`
-	CPUAffinity            *string `hashcat:"--cpu-affinity,omitempty"`
-	HookThreads            *int    `hashcat:"--hook-threads,omitempty"`
-	BackendIgnoreCUDA      *bool   `hashcat:"--backend-ignore-cuda,omitempty"`
-	BackendIgnoreOpenCL    *bool   `hashcat:"--backend-ignore-opencl,omitempty"`
-	BackendDevices         *string `hashcat:"--backend-devices,omitempty"`
-	OpenCLDeviceTypes      *string `hashcat:"--opencl-device-types,omitempty"`
-	OptimizedKernelEnabled *bool   `hashcat:"--optimized-kernel-enable,omitempty"`
-	WorkloadProfile        *int    `hashcat:"--workload-profile,omitempty"`
-	KernelAccel            *int    `hashcat:"--kernel-accel,omitempty"`
-	KernelLoops            *int    `hashcat:"--kernel-loops,omitempty"`
-	SpinDamp               *int    `hashcat:"--spin-damp,omitempty"`
-	HWMonitorDisable       *bool   `hashcat:"--hwmon-disable,omitempty"`
-	HWMonitorTempAbort     *int    `hashcat:"--hwmon-temp-abort,omitempty"`
-	ScryptTMTO             *int    `hashcat:"--scrypt-tmto,omitempty"`
-	Skip                   *int    `hashcat:"--skip,omitempty"`
-	Limit                  *int    `hashcat:"--limit,omitempty"`
-	RuleLeft               *string `hashcat:"--rule-left,omitempty"`
-	RuleRight              *string `hashcat:"--rule-right,omitempty"`
-	RulesFile              *string `hashcat:"--rules-file,omitempty"`
-	GenerateRules          *int    `hashcat:"--generate-rules,omitempty"`
-	GenerateRulesFuncMin   *int    `hashcat:"--generate-rules-func-min,omitempty"`
-	GenerateRulesFuncMax   *int    `hashcat:"--generate-rules-func-max,omitempty"`
-	GenerateRulesSeed      *int    `hashcat:"--generate-rules-seed,omitempty"`
-	CustomCharset1         *string `hashcat:"--custom-charset1,omitempty"`
-	CustomCharset2         *string `hashcat:"--custom-charset2,omitempty"`
-	CustomCharset3         *string `hashcat:"--custom-charset3,omitempty"`
-	CustomCharset4         *string `hashcat:"--custom-charset4,omitempty"`
-	IncrementMask          *bool   `hashcat:"--increment,omitempty"`
-	IncrementMaskMin       *int    `hashcat:"--increment-min,omitempty"`
-	IncrementMaskMax       *int    `hashcat:"--increment-max,omitempty"`
-	Identify               *bool   `hashcat:"--identify,omitempty"`
-	EnableDeprecated       *bool   `hashcat:"--deprecated-check-disable,omitempty"`
-
-	// InputFile can be a single hash or multiple hashes via a hashfile or hccapx
-	InputFile                    string  `hashcat:","`
-	DictionaryMaskDirectoryInput *string `hashcat:",omitempty"`
-}
-
 func parseTag(t string) (tag, options string) {
 	if idx := strings.Index(t, ","); idx != -1 {
 		return t[:idx], t[idx+1:]
 	}
-	return tag, ""
+	return t, ""
 }
 
-// ParseOptions parses the options string and returns a HashcatSessionOptions struct
+// ParseOptions parses a hashcat command line and returns a HashcatSessionOptions struct.
+// optionsString is split using shell-style quoting (see SplitCommandLine) and accepts long flags (--hash-type=0 or --hash-type 0),
+// short flags (-m 0, -m0, -O), and the positional hash and dictionary/mask/directory arguments.
+// A leading hashcat binary (hashcat, hashcat.exe, etc) is ignored
 func ParseOptions(optionsString string) (*HashcatSessionOptions, error) {
-	options := &HashcatSessionOptions{}
-	flagSet := flag.NewFlagSet("hashcat", flag.ContinueOnError)
-	flagSet.SetOutput(io.Discard)
-
-	v := reflect.ValueOf(options).Elem()
-	t := v.Type()
-
-	flagNames := make([]string, v.NumField())
-
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		tag := t.Field(i).Tag.Get("hashcat")
-
-		if tag == "" || !field.CanSet() {
-			continue
-		}
-
-		name := strings.Split(tag, ",")[0]
-		name = strings.TrimPrefix(name, "--")
-		flagNames[i] = name
-
-		switch field.Kind() {
-		case reflect.Ptr:
-			switch field.Type().Elem().Kind() {
-			case reflect.Int:
-				field.Set(reflect.ValueOf(flagSet.Int(name, 0, "")))
-			case reflect.Bool:
-				field.Set(reflect.ValueOf(flagSet.Bool(name, false, "")))
-			case reflect.String:
-				field.Set(reflect.ValueOf(flagSet.String(name, "", "")))
-			}
-		}
-	}
-
-	args := strings.Fields(optionsString)
-	err := flagSet.Parse(args)
+	args, err := SplitCommandLine(optionsString)
 	if err != nil {
 		return nil, err
 	}
+	return UnmarshalOptions{SkipProgram: len(args) > 0 && isProgramName(args[0])}.Unmarshal(args)
+}
 
-	// Validate that all provided arguments are valid
-	// Validate that all provided arguments are valid
-	for _, arg := range args {
-		if !strings.HasPrefix(arg, "--") {
-			return nil, fmt.Errorf("invalid argument: %s", arg)
-		}
-		valid := false
-		argName := strings.Split(arg, "=")[0] // Split the argument into flag name and value
-		for _, name := range flagNames {
-			if "--"+name == argName {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			return nil, fmt.Errorf("invalid argument: %s", arg)
-		}
-	}
+// UnmarshalArgs is the reverse of MarshalArgs, it parses a list of hashcat arguments into options.
+// Flags can be long (--hash-type=0 or --hash-type 0) or short (-m 0, -m0, -O), the first positional argument is the
+// InputFile and the rest are the DictionaryMaskDirectoryInput. Use UnmarshalOptions to parse restoreutil.RestoreData.Args
+// or arguments without an InputFile.
+// Unknown flags are reported together in an *UnknownArgsError.
+// Note that MarshalArgs skips boolean options that are set to false so they'll be nil once unmarshaled
+func UnmarshalArgs(args []string) (*HashcatSessionOptions, error) {
+	return UnmarshalOptions{}.Unmarshal(args)
+}
 
-	for i, name := range flagNames {
-		if name != "" && !isFlagPassed(flagSet, name) {
-			v.Field(i).Set(reflect.Zero(v.Field(i).Type()))
-		}
-	}
+// UnmarshalOptions changes how arguments are parsed by Unmarshal
+type UnmarshalOptions struct {
+	// SkipProgram drops the first argument. It should be set for restoreutil.RestoreData.Args, which begin with the
+	// executable that started the session. For gocat that's the host program rather than hashcat
+	SkipProgram bool
 
-	return options, nil
+	// NoInputFile parses every positional argument into DictionaryMaskDirectoryInput. It should be set for the arguments
+	// of sessions that don't take any hashes (keyspace, benchmark, and candidate generation), where MarshalArgs was called
+	// with an empty InputFile
+	NoInputFile bool
 }
 
-func isFlagPassed(flagSet *flag.FlagSet, name string) bool {
-	found := false
-	flagSet.Visit(func(f *flag.Flag) {
-		if f.Name == name {
-			found = true
-		}
-	})
-	return found
+// Unmarshal parses args the same way as UnmarshalArgs, following the settings in o
+func (o UnmarshalOptions) Unmarshal(args []string) (*HashcatSessionOptions, error) {
+	if o.SkipProgram && len(args) > 0 {
+		args = args[1:]
+	}
+	return parseArgs(args, !o.NoInputFile)
 }
 
-// MarshalArgs returns a list of arguments set by the user to be passed into hashcat's session for execution
+// MarshalArgs returns a list of arguments set by the user to be passed into hashcat's session for execution.
+// Slice fields are passed as a repeated flag (--rules-file=a --rules-file=b) unless their tag has the comma option,
+// in which case they're passed once with their values separated by commas (--truecrypt-keyfiles=a,b)
 func (o HashcatSessionOptions) MarshalArgs() (args []string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -267,6 +128,27 @@ func (o HashcatSessionOptions) MarshalArgs() (args []string, err error) {
 			} else {
 				args = append(args, val.String())
 			}
+		case reflect.Slice:
+			values, sliceErr := sliceValues(val)
+			if sliceErr != nil {
+				err = sliceErr
+				return
+			}
+
+			if len(values) == 0 {
+				continue
+			}
+
+			switch {
+			case name == "":
+				args = append(args, values...)
+			case strings.Contains(opts, "comma"):
+				args = append(args, fmt.Sprintf("%s=%s", name, strings.Join(values, ",")))
+			default:
+				for _, value := range values {
+					args = append(args, fmt.Sprintf("%s=%s", name, value))
+				}
+			}
 		default:
 			err = fmt.Errorf("unknown type %s", val.Type().Kind())
 			return
@@ -274,3 +156,19 @@ func (o HashcatSessionOptions) MarshalArgs() (args []string, err error) {
 	}
 	return
 }
+
+// sliceValues formats the elements of a []string or []int field
+func sliceValues(val reflect.Value) ([]string, error) {
+	values := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		switch elem := val.Index(i); elem.Kind() {
+		case reflect.String:
+			values = append(values, elem.String())
+		case reflect.Int:
+			values = append(values, strconv.Itoa(int(elem.Int())))
+		default:
+			return nil, fmt.Errorf("unknown type []%s", elem.Kind())
+		}
+	}
+	return values, nil
+}
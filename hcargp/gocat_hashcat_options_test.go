@@ -10,7 +10,7 @@ import (
 
 func ExampleHashcatSessionOptions_MarshalArgs() {
 	opts := HashcatSessionOptions{
-		AttackMode:     GetIntPtr(0),
+		AttackMode:     GetAttackModePtr(AttackStraight),
 		HashType:       GetIntPtr(0),
 		SessionName:    GetStringPtr("example_args_session"),
 		PotfileDisable: GetBoolPtr(true),
@@ -46,7 +46,7 @@ func TestHashcatSessionOptionsMarshalArgs(t *testing.T) {
 	}{
 		{
 			opts: HashcatSessionOptions{
-				AttackMode: GetIntPtr(0),
+				AttackMode: GetAttackModePtr(AttackStraight),
 				HashType:   nil,
 				InputFile:  "deadbeefdeadbeefdeadbeefdeadbeef",
 			},
@@ -71,13 +71,33 @@ func TestHashcatSessionOptionsMarshalArgs(t *testing.T) {
 		},
 		{
 			opts: HashcatSessionOptions{
-				AttackMode:                   GetIntPtr(0),
+				AttackMode:                   GetAttackModePtr(AttackStraight),
 				InputFile:                    "deadbeefdeadbeefdeadbeefdeadbeef",
-				DictionaryMaskDirectoryInput: GetStringPtr("./testdata/test_dictionary.txt"),
+				DictionaryMaskDirectoryInput: []string{"./testdata/test_dictionary.txt"},
 			},
 			expectedError: nil,
 			expectedArgs:  []string{"--attack-mode=0", "deadbeefdeadbeefdeadbeefdeadbeef", "./testdata/test_dictionary.txt"},
 		},
+		{
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackCombinator),
+				RulesFile:                    []string{"best64.rule", "toggles1.rule"},
+				TrueCryptKeyFiles:            []string{"a.png", "b.png"},
+				InputFile:                    "deadbeefdeadbeefdeadbeefdeadbeef",
+				DictionaryMaskDirectoryInput: []string{"left.txt", "right.txt"},
+			},
+			expectedError: nil,
+			expectedArgs: []string{"--attack-mode=1", "--truecrypt-keyfiles=a.png,b.png", "--rules-file=best64.rule",
+				"--rules-file=toggles1.rule", "deadbeefdeadbeefdeadbeefdeadbeef", "left.txt", "right.txt"},
+		},
+		{
+			opts: HashcatSessionOptions{
+				RulesFile: []string{},
+				InputFile: "deadbeefdeadbeefdeadbeefdeadbeef",
+			},
+			expectedError: nil,
+			expectedArgs:  []string{"deadbeefdeadbeefdeadbeefdeadbeef"},
+		},
 	} {
 		args, err := test.opts.MarshalArgs()
 
@@ -0,0 +1,230 @@
+// Code automatically generated; DO NOT EDIT.
+
+package hcargp
+
+// HashcatSessionOptions represents all the available hashcat options. The values here should always follow the latest version of hashcat
+type HashcatSessionOptions struct {
+	// HashType (-m, --hash-type): Hash-type, references below (otherwise autodetect)
+	HashType *int `hashcat:"--hash-type,omitempty" short:"-m"`
+	// AttackMode (-a, --attack-mode): Attack-mode, see references below
+	AttackMode *AttackMode `hashcat:"--attack-mode,omitempty" short:"-a"`
+	// IsHexCharset (--hex-charset): Assume charset is given in hex
+	IsHexCharset *bool `hashcat:"--hex-charset,omitempty"`
+	// IsHexSalt (--hex-salt): Assume salt is given in hex
+	IsHexSalt *bool `hashcat:"--hex-salt,omitempty"`
+	// IsHexWordlist (--hex-wordlist): Assume words in wordlist are given in hex
+	IsHexWordlist *bool `hashcat:"--hex-wordlist,omitempty"`
+	// Force (--force): Ignore warnings
+	Force *bool `hashcat:"--force,omitempty"`
+	// EnableDeprecated (--deprecated-check-disable): Enable deprecated plugins
+	EnableDeprecated *bool `hashcat:"--deprecated-check-disable,omitempty"`
+	// StdinTimeoutAbort (--stdin-timeout-abort): Abort if there is no input from stdin for X seconds
+	StdinTimeoutAbort *int `hashcat:"--stdin-timeout-abort,omitempty"`
+	// KeepGuessing (--keep-guessing): Keep guessing the hash after it has been cracked
+	KeepGuessing *bool `hashcat:"--keep-guessing,omitempty"`
+	// SelfTestDisable (--self-test-disable): Disable self-test functionality on startup
+	SelfTestDisable *bool `hashcat:"--self-test-disable,omitempty"`
+	// Loopback (--loopback): Add new plains to induct directory
+	Loopback *bool `hashcat:"--loopback,omitempty"`
+	// MarkovHCStat2 (--markov-hcstat2): Specify hcstat2 file to use
+	MarkovHCStat2 *string `hashcat:"--markov-hcstat2,omitempty"`
+	// DisableMarkov (--markov-disable): Disables markov-chains, emulates classic brute-force
+	DisableMarkov *bool `hashcat:"--markov-disable,omitempty"`
+	// EnableClassicMarkov (--markov-classic): Enables classic markov-chains, no per-position
+	EnableClassicMarkov *bool `hashcat:"--markov-classic,omitempty"`
+	// MarkovInverse (--markov-inverse): Enables inverse markov-chains, no per-position
+	MarkovInverse *bool `hashcat:"--markov-inverse,omitempty"`
+	// MarkovThreshold (-t, --markov-threshold): Threshold X when to stop accepting new markov-chains
+	MarkovThreshold *int `hashcat:"--markov-threshold,omitempty" short:"-t"`
+	// MaxRuntimeSeconds (--runtime): Abort session after X seconds of runtime
+	MaxRuntimeSeconds *int `hashcat:"--runtime,omitempty"`
+	// SessionName (--session): Define specific session name
+	SessionName *string `hashcat:"--session,omitempty"`
+	// RestoreSession (--restore): Restore session from --session
+	RestoreSession *bool `hashcat:"--restore,omitempty"`
+	// DisableRestore (--restore-disable): Do not write restore file
+	DisableRestore *bool `hashcat:"--restore-disable,omitempty"`
+	// RestoreFilePath (--restore-file-path): Specific path to restore file
+	RestoreFilePath *string `hashcat:"--restore-file-path,omitempty"`
+	// OutfilePath (-o, --outfile): Define outfile for recovered hash
+	OutfilePath *string `hashcat:"--outfile,omitempty" short:"-o"`
+	// OutfileFormat (--outfile-format): Outfile format to use, separated with commas
+	OutfileFormat []OutfileFormat `hashcat:"--outfile-format,omitempty,comma"`
+	// OutfileJSON (--outfile-json): Force JSON format in outfile format
+	OutfileJSON *bool `hashcat:"--outfile-json,omitempty"`
+	// OutfileDisableAutoHex (--outfile-autohex-disable): Disable the use of $HEX[] in output plains
+	OutfileDisableAutoHex *bool `hashcat:"--outfile-autohex-disable,omitempty"`
+	// OutfileCheckTimer (--outfile-check-timer): Sets seconds between outfile checks to X
+	OutfileCheckTimer *int `hashcat:"--outfile-check-timer,omitempty"`
+	// WordlistAutohexDisable (--wordlist-autohex-disable): Disable the conversion of $HEX[] from the wordlist
+	WordlistAutohexDisable *bool `hashcat:"--wordlist-autohex-disable,omitempty"`
+	// Separator (-p, --separator): Separator char for hashlists and outfile
+	Separator *string `hashcat:"--separator,omitempty" short:"-p"`
+	// IgnoreUsername (--username): Enable ignoring of usernames in hashfile
+	IgnoreUsername *bool `hashcat:"--username,omitempty"`
+	// DynamicX (--dynamic-x): Ignore $dynamic_X$ prefix in hashes
+	DynamicX *bool `hashcat:"--dynamic-x,omitempty"`
+	// RemoveCrackedHash (--remove): Enable removal of hashes once they are cracked
+	RemoveCrackedHash *bool `hashcat:"--remove,omitempty"`
+	// RemoveCrackedHashTimer (--remove-timer): Update input hash file each X seconds
+	RemoveCrackedHashTimer *int `hashcat:"--remove-timer,omitempty"`
+	// PotfileDisable (--potfile-disable): Do not write potfile
+	PotfileDisable *bool `hashcat:"--potfile-disable,omitempty"`
+	// PotfilePath (--potfile-path): Specific path to potfile
+	PotfilePath *string `hashcat:"--potfile-path,omitempty"`
+	// EncodingFrom (--encoding-from): Force internal wordlist encoding from X
+	EncodingFrom *string `hashcat:"--encoding-from,omitempty"`
+	// EncodingTo (--encoding-to): Force internal wordlist encoding to X
+	EncodingTo *string `hashcat:"--encoding-to,omitempty"`
+	// DebugMode (--debug-mode): Defines the debug mode (hybrid only by using rules)
+	DebugMode *DebugMode `hashcat:"--debug-mode,omitempty"`
+	// DebugFile (--debug-file): Output file for debugging rules
+	DebugFile *string `hashcat:"--debug-file,omitempty"`
+	// InductionDir (--induction-dir): Specify the induction directory to use for loopback
+	InductionDir *string `hashcat:"--induction-dir,omitempty"`
+	// OutfileCheckDir (--outfile-check-dir): Specify the outfile directory to monitor for plains
+	OutfileCheckDir *string `hashcat:"--outfile-check-dir,omitempty"`
+	// LogfileDisable (--logfile-disable): Disable the logfile
+	LogfileDisable *bool `hashcat:"--logfile-disable,omitempty"`
+	// HccapxMessagePair (--hccapx-message-pair): Load only message pairs from hccapx matching X
+	HccapxMessagePair *int `hashcat:"--hccapx-message-pair,omitempty"`
+	// NonceErrorCorrections (--nonce-error-corrections): The BF size range to replace AP's nonce last bytes
+	NonceErrorCorrections *int `hashcat:"--nonce-error-corrections,omitempty"`
+	// KeyboardLayoutMapping (--keyboard-layout-mapping): Keyboard layout mapping table for special hash-modes
+	KeyboardLayoutMapping *string `hashcat:"--keyboard-layout-mapping,omitempty"`
+	// TrueCryptKeyFiles (--truecrypt-keyfiles): Keyfiles to use, separated with commas
+	TrueCryptKeyFiles []string `hashcat:"--truecrypt-keyfiles,omitempty,comma"`
+	// VeraCryptKeyFiles (--veracrypt-keyfiles): Keyfiles to use, separated with commas
+	VeraCryptKeyFiles []string `hashcat:"--veracrypt-keyfiles,omitempty,comma"`
+	// VeraCryptPIMStart (--veracrypt-pim-start): VeraCrypt personal iterations multiplier start
+	VeraCryptPIMStart *int `hashcat:"--veracrypt-pim-start,omitempty"`
+	// VeraCryptPIMStop (--veracrypt-pim-stop): VeraCrypt personal iterations multiplier stop
+	VeraCryptPIMStop *int `hashcat:"--veracrypt-pim-stop,omitempty"`
+	// SegmentSize (-c, --segment-size): Sets size in MB to cache from the wordfile to X
+	SegmentSize *int `hashcat:"--segment-size,omitempty" short:"-c"`
+	// BitmapMin (--bitmap-min): Sets minimum bits allowed for bitmaps to X
+	BitmapMin *int `hashcat:"--bitmap-min,omitempty"`
+	// BitmapMax (--bitmap-max): Sets maximum bits allowed for bitmaps to X
+	BitmapMax *int `hashcat:"--bitmap-max,omitempty"`
+	// BridgeParameter1 (--bridge-parameter1): Sets the generic parameter 1 for a Bridge
+	BridgeParameter1 *string `hashcat:"--bridge-parameter1,omitempty"`
+	// BridgeParameter2 (--bridge-parameter2): Sets the generic parameter 2 for a Bridge
+	BridgeParameter2 *string `hashcat:"--bridge-parameter2,omitempty"`
+	// BridgeParameter3 (--bridge-parameter3): Sets the generic parameter 3 for a Bridge
+	BridgeParameter3 *string `hashcat:"--bridge-parameter3,omitempty"`
+	// BridgeParameter4 (--bridge-parameter4): Sets the generic parameter 4 for a Bridge
+	BridgeParameter4 *string `hashcat:"--bridge-parameter4,omitempty"`
+	// CPUAffinity (--cpu-affinity): Locks to CPU devices, separated with commas
+	CPUAffinity *string `hashcat:"--cpu-affinity,omitempty"`
+	// HookThreads (--hook-threads): Sets number of threads for a hook (per compute unit)
+	HookThreads *int `hashcat:"--hook-threads,omitempty"`
+	// BackendIgnoreCUDA (--backend-ignore-cuda): Do not try to open CUDA interface on startup
+	BackendIgnoreCUDA *bool `hashcat:"--backend-ignore-cuda,omitempty"`
+	// BackendIgnoreHIP (--backend-ignore-hip): Do not try to open HIP interface on startup
+	BackendIgnoreHIP *bool `hashcat:"--backend-ignore-hip,omitempty"`
+	// BackendIgnoreMetal (--backend-ignore-metal): Do not try to open Metal interface on startup
+	BackendIgnoreMetal *bool `hashcat:"--backend-ignore-metal,omitempty"`
+	// BackendIgnoreOpenCL (--backend-ignore-opencl): Do not try to open OpenCL interface on startup
+	BackendIgnoreOpenCL *bool `hashcat:"--backend-ignore-opencl,omitempty"`
+	// BackendDevices (-d, --backend-devices): Backend devices to use, separated with commas
+	BackendDevices *string `hashcat:"--backend-devices,omitempty" short:"-d"`
+	// BackendDevicesVirtmulti (-Y, --backend-devices-virtmulti): Spawn X virtual instances for each real device
+	BackendDevicesVirtmulti *int `hashcat:"--backend-devices-virtmulti,omitempty" short:"-Y"`
+	// BackendDevicesVirthost (-R, --backend-devices-virthost): Sets the real device to create virtual instances
+	BackendDevicesVirthost *int `hashcat:"--backend-devices-virthost,omitempty" short:"-R"`
+	// BackendDevicesKeepfree (--backend-devices-keepfree): Keep specified percentage of device memory free
+	BackendDevicesKeepfree *int `hashcat:"--backend-devices-keepfree,omitempty"`
+	// OpenCLDeviceTypes (-D, --opencl-device-types): OpenCL device-types to use, separated with commas
+	OpenCLDeviceTypes *string `hashcat:"--opencl-device-types,omitempty" short:"-D"`
+	// OptimizedKernelEnabled (-O, --optimized-kernel-enable): Enable optimized kernels (limits password length)
+	OptimizedKernelEnabled *bool `hashcat:"--optimized-kernel-enable,omitempty" short:"-O"`
+	// MultiplyAccelDisable (-M, --multiply-accel-disable): Disable multiply kernel-accel with processor count
+	MultiplyAccelDisable *bool `hashcat:"--multiply-accel-disable,omitempty" short:"-M"`
+	// WorkloadProfile (-w, --workload-profile): Enable a specific workload profile, see pool below
+	WorkloadProfile *WorkloadProfile `hashcat:"--workload-profile,omitempty" short:"-w"`
+	// KernelAccel (-n, --kernel-accel): Manual workload tuning, set outerloop step size to X
+	KernelAccel *int `hashcat:"--kernel-accel,omitempty" short:"-n"`
+	// KernelLoops (-u, --kernel-loops): Manual workload tuning, set innerloop step size to X
+	KernelLoops *int `hashcat:"--kernel-loops,omitempty" short:"-u"`
+	// KernelThreads (-T, --kernel-threads): Manual workload tuning, set thread count to X
+	KernelThreads *int `hashcat:"--kernel-threads,omitempty" short:"-T"`
+	// BackendVectorWidth (--backend-vector-width): Manually override backend vector-width to X
+	BackendVectorWidth *int `hashcat:"--backend-vector-width,omitempty"`
+	// SpinDamp (--spin-damp): Use CPU for device synchronization, in percent
+	SpinDamp *int `hashcat:"--spin-damp,omitempty"`
+	// HWMonitorDisable (--hwmon-disable): Disable temperature and fanspeed reads and triggers
+	HWMonitorDisable *bool `hashcat:"--hwmon-disable,omitempty"`
+	// HWMonitorTempAbort (--hwmon-temp-abort): Abort if temperature reaches X degrees Celsius
+	HWMonitorTempAbort *int `hashcat:"--hwmon-temp-abort,omitempty"`
+	// ScryptTMTO (--scrypt-tmto): Manually override TMTO value for scrypt to X
+	ScryptTMTO *int `hashcat:"--scrypt-tmto,omitempty"`
+	// Skip (-s, --skip): Skip X words from the start
+	Skip *int `hashcat:"--skip,omitempty" short:"-s"`
+	// Limit (-l, --limit): Limit X words from the start + skipped words
+	Limit *int `hashcat:"--limit,omitempty" short:"-l"`
+	// RuleLeft (-j, --rule-left): Single rule applied to each word from left wordlist
+	RuleLeft *string `hashcat:"--rule-left,omitempty" short:"-j"`
+	// RuleRight (-k, --rule-right): Single rule applied to each word from right wordlist
+	RuleRight *string `hashcat:"--rule-right,omitempty" short:"-k"`
+	// RulesFile (-r, --rules-file): Multiple rules applied to each word from wordlists
+	RulesFile []string `hashcat:"--rules-file,omitempty" short:"-r"`
+	// GenerateRules (-g, --generate-rules): Generate X random rules
+	GenerateRules *int `hashcat:"--generate-rules,omitempty" short:"-g"`
+	// GenerateRulesFuncMin (--generate-rules-func-min): Force min X functions per rule
+	GenerateRulesFuncMin *int `hashcat:"--generate-rules-func-min,omitempty"`
+	// GenerateRulesFuncMax (--generate-rules-func-max): Force max X functions per rule
+	GenerateRulesFuncMax *int `hashcat:"--generate-rules-func-max,omitempty"`
+	// GenerateRulesFuncSel (--generate-rules-func-sel): Pool of rule operators valid for random rule engine
+	GenerateRulesFuncSel *string `hashcat:"--generate-rules-func-sel,omitempty"`
+	// GenerateRulesSeed (--generate-rules-seed): Force RNG seed set to X
+	GenerateRulesSeed *int `hashcat:"--generate-rules-seed,omitempty"`
+	// CustomCharset1 (-1, --custom-charset1): User-defined charset ?1
+	CustomCharset1 *string `hashcat:"--custom-charset1,omitempty" short:"-1"`
+	// CustomCharset2 (-2, --custom-charset2): User-defined charset ?2
+	CustomCharset2 *string `hashcat:"--custom-charset2,omitempty" short:"-2"`
+	// CustomCharset3 (-3, --custom-charset3): User-defined charset ?3
+	CustomCharset3 *string `hashcat:"--custom-charset3,omitempty" short:"-3"`
+	// CustomCharset4 (-4, --custom-charset4): User-defined charset ?4
+	CustomCharset4 *string `hashcat:"--custom-charset4,omitempty" short:"-4"`
+	// Identify (--identify): Shows all supported algorithms for input hashes
+	Identify *bool `hashcat:"--identify,omitempty"`
+	// IncrementMask (-i, --increment): Enable mask increment mode
+	IncrementMask *bool `hashcat:"--increment,omitempty" short:"-i"`
+	// IncrementMaskMin (--increment-min): Start mask incrementing at X
+	IncrementMaskMin *int `hashcat:"--increment-min,omitempty"`
+	// IncrementMaskMax (--increment-max): Stop mask incrementing at X
+	IncrementMaskMax *int `hashcat:"--increment-max,omitempty"`
+	// SlowCandidates (-S, --slow-candidates): Enable slower (but advanced) candidate generators
+	SlowCandidates *bool `hashcat:"--slow-candidates,omitempty" short:"-S"`
+	// BrainClient (-z, --brain-client): Enable brain client, activates -S
+	BrainClient *bool `hashcat:"--brain-client,omitempty" short:"-z"`
+	// BrainClientFeatures (--brain-client-features): Define brain client features, see below
+	BrainClientFeatures *int `hashcat:"--brain-client-features,omitempty"`
+	// BrainHost (--brain-host): Brain server host (IP or domain)
+	BrainHost *string `hashcat:"--brain-host,omitempty"`
+	// BrainPort (--brain-port): Brain server port
+	BrainPort *int `hashcat:"--brain-port,omitempty"`
+	// BrainPassword (--brain-password): Brain server authentication password
+	BrainPassword *string `hashcat:"--brain-password,omitempty"`
+	// BrainSession (--brain-session): Overrides automatically calculated brain session
+	BrainSession *string `hashcat:"--brain-session,omitempty"`
+	// BrainSessionWhitelist (--brain-session-whitelist): Allow given sessions only, separated with commas
+	BrainSessionWhitelist *string `hashcat:"--brain-session-whitelist,omitempty"`
+	// WeakHashThreshold (--weak-hash-threshold) is no longer supported by hashcat.
+	//
+	// Deprecated: --weak-hash-threshold has been removed from hashcat and will be rejected.
+	WeakHashThreshold *int `hashcat:"--weak-hash-threshold,omitempty"`
+	// MarkovHCStat (--markov-hcstat) is no longer supported by hashcat.
+	//
+	// Deprecated: --markov-hcstat has been removed from hashcat and will be rejected.
+	MarkovHCStat *string `hashcat:"--markov-hcstat,omitempty"`
+	// VeraCryptPIM (--veracrypt-pim) is no longer supported by hashcat.
+	//
+	// Deprecated: --veracrypt-pim has been removed from hashcat and will be rejected.
+	VeraCryptPIM *int `hashcat:"--veracrypt-pim,omitempty"`
+
+	// InputFile can be a single hash or multiple hashes via a hashfile or hccapx
+	InputFile string `hashcat:","`
+	// DictionaryMaskDirectoryInput are the dictionaries, masks, or directories used by the attack mode
+	DictionaryMaskDirectoryInput []string `hashcat:",omitempty"`
+}
@@ -0,0 +1,261 @@
+package hcargp
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnterminatedQuote is raised when a command line has a quote without its closing pair
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// SplitCommandLine splits s into arguments the way a posix shell would. Arguments are separated by whitespace,
+// single quotes preserve everything literally, double quotes allow \" and \\ escapes, and a backslash outside
+// of quotes escapes the next character
+func SplitCommandLine(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end == -1 {
+				return nil, ErrUnterminatedQuote
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) != -1 {
+					i++
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			inArg = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			cur.WriteByte(s[i])
+			inArg = true
+		default:
+			cur.WriteByte(c)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// optionField is a field of HashcatSessionOptions that can be set from the command line
+type optionField struct {
+	index int
+	name  string
+	comma bool
+}
+
+// optionFields maps every long (--hash-type) and short (-m) flag to its field
+func optionFields() map[string]optionField {
+	t := reflect.TypeOf(HashcatSessionOptions{})
+	fields := make(map[string]optionField, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		name, opts := parseTag(t.Field(i).Tag.Get("hashcat"))
+		if name == "" {
+			continue
+		}
+
+		field := optionField{index: i, name: name, comma: strings.Contains(opts, "comma")}
+		fields[name] = field
+		if short := t.Field(i).Tag.Get("short"); short != "" {
+			fields[short] = field
+		}
+	}
+	return fields
+}
+
+// UnknownArgsError is returned when a command line contains flags that aren't part of HashcatSessionOptions
+type UnknownArgsError struct {
+	Args []string
+}
+
+func (e *UnknownArgsError) Error() string {
+	if len(e.Args) == 1 {
+		return "invalid argument: " + e.Args[0]
+	}
+	return "invalid arguments: " + strings.Join(e.Args, ", ")
+}
+
+// isProgramName reports if arg is the hashcat binary at the front of a pasted command line
+func isProgramName(arg string) bool {
+	base := strings.ToLower(filepath.Base(filepath.ToSlash(arg)))
+	switch base {
+	case "hashcat", "hashcat.exe", "hashcat.bin", "hashcat64.exe", "hashcat64.bin":
+		return true
+	}
+	return false
+}
+
+// parseArgs parses hashcat's arguments, without the program name, into options. If inputFile is set the first
+// positional argument is the hash or hashfile, the rest are the dictionaries, masks, or directories used by the attack
+func parseArgs(args []string, inputFile bool) (*HashcatSessionOptions, error) {
+	options := &HashcatSessionOptions{}
+	v := reflect.ValueOf(options).Elem()
+	fields := optionFields()
+
+	var positionals, unknown []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			positionals = append(positionals, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg, "=")
+			field, ok := fields[name]
+			if !ok {
+				unknown = append(unknown, name)
+				continue
+			}
+
+			if !hasValue && v.Field(field.index).Type().Elem().Kind() != reflect.Bool {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for %s", name)
+				}
+				i++
+				value = args[i]
+			}
+
+			if err := setOption(v.Field(field.index), field, value, hasValue); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// short flags can be bundled (-Oi) and take their value from the rest of the argument (-m0) or the next one (-m 0)
+			for j := 1; j < len(arg); j++ {
+				name := "-" + arg[j:j+1]
+				field, ok := fields[name]
+				if !ok {
+					// we can't tell if the rest of the argument is the value of an unknown flag
+					unknown = append(unknown, name)
+					break
+				}
+
+				fv := v.Field(field.index)
+				if fv.Type().Elem().Kind() == reflect.Bool {
+					if err := setOption(fv, field, "", false); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				value := arg[j+1:]
+				if value == "" {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for %s", name)
+					}
+					i++
+					value = args[i]
+				}
+
+				if err := setOption(fv, field, value, true); err != nil {
+					return nil, err
+				}
+				break
+			}
+		default:
+			positionals = append(positionals, arg)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, &UnknownArgsError{Args: unknown}
+	}
+
+	if inputFile && len(positionals) > 0 {
+		options.InputFile = positionals[0]
+		positionals = positionals[1:]
+	}
+
+	if len(positionals) > 0 {
+		options.DictionaryMaskDirectoryInput = positionals
+	}
+
+	return options, nil
+}
+
+// setOption sets the field fv to value. Bool fields are set to true unless an explicit value was given.
+// Values are appended to slice fields, split on commas when the field's tag has the comma option
+func setOption(fv reflect.Value, field optionField, value string, hasValue bool) error {
+	name := field.name
+
+	if fv.Kind() == reflect.Slice {
+		values := []string{value}
+		if field.comma {
+			values = strings.Split(value, ",")
+		}
+
+		for _, value := range values {
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			switch elem.Kind() {
+			case reflect.String:
+				elem.SetString(value)
+			case reflect.Int:
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid value %q for %s: expected a number", value, name)
+				}
+				elem.SetInt(int64(n))
+			default:
+				return fmt.Errorf("unknown type []%s", elem.Kind())
+			}
+			fv.Set(reflect.Append(fv, elem))
+		}
+		return nil
+	}
+
+	ptr := reflect.New(fv.Type().Elem())
+
+	switch fv.Type().Elem().Kind() {
+	case reflect.Bool:
+		b := true
+		if hasValue {
+			var err error
+			if b, err = strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid value %q for %s: expected a boolean", value, name)
+			}
+		}
+		ptr.Elem().SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: expected a number", value, name)
+		}
+		ptr.Elem().SetInt(int64(n))
+	case reflect.String:
+		ptr.Elem().SetString(value)
+	default:
+		return fmt.Errorf("unknown type %s", fv.Type().Elem().Kind())
+	}
+
+	fv.Set(ptr)
+	return nil
+}
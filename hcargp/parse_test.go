@@ -0,0 +1,156 @@
+package hcargp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		expected []string
+		err      error
+	}{
+		{
+			line:     "-m 0  -a\t3 hash ?d?d",
+			expected: []string{"-m", "0", "-a", "3", "hash", "?d?d"},
+		},
+		{
+			line:     `-r "my rules/best64.rule" 'C:\wordlists\rock you.txt'`,
+			expected: []string{"-r", "my rules/best64.rule", `C:\wordlists\rock you.txt`},
+		},
+		{
+			line:     `--separator=\: "say \"hi\"" a\ b ''`,
+			expected: []string{"--separator=:", `say "hi"`, "a b", ""},
+		},
+		{
+			line:     `--outfile="out file.txt"`,
+			expected: []string{"--outfile=out file.txt"},
+		},
+		{
+			line: `-r "unterminated`,
+			err:  ErrUnterminatedQuote,
+		},
+		{
+			line: `-r 'unterminated`,
+			err:  ErrUnterminatedQuote,
+		},
+	} {
+		args, err := SplitCommandLine(test.line)
+		assert.Equal(t, test.err, err, test.line)
+		assert.Equal(t, test.expected, args, test.line)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(`hashcat -m 1000 -a0 -w 3 -O --potfile-disable -r "rules/best 64.rule" --session=test hashes.txt "word lists/rockyou.txt"`)
+	require.NoError(t, err)
+	assert.Equal(t, &HashcatSessionOptions{
+		HashType:                     GetIntPtr(1000),
+		AttackMode:                   GetAttackModePtr(AttackStraight),
+		WorkloadProfile:              GetWorkloadProfilePtr(WorkloadHigh),
+		OptimizedKernelEnabled:       GetBoolPtr(true),
+		PotfileDisable:               GetBoolPtr(true),
+		RulesFile:                    []string{"rules/best 64.rule"},
+		SessionName:                  GetStringPtr("test"),
+		InputFile:                    "hashes.txt",
+		DictionaryMaskDirectoryInput: []string{"word lists/rockyou.txt"},
+	}, opts)
+
+	opts, err = ParseOptions("./hashcat.bin -Oi --increment-min 2 -1 ?l?d -a 3 --hash-type 0 --force=false deadbeef ?1?1?1")
+	require.NoError(t, err)
+	assert.Equal(t, &HashcatSessionOptions{
+		OptimizedKernelEnabled:       GetBoolPtr(true),
+		IncrementMask:                GetBoolPtr(true),
+		IncrementMaskMin:             GetIntPtr(2),
+		CustomCharset1:               GetStringPtr("?l?d"),
+		AttackMode:                   GetAttackModePtr(AttackBruteForce),
+		HashType:                     GetIntPtr(0),
+		Force:                        GetBoolPtr(false),
+		InputFile:                    "deadbeef",
+		DictionaryMaskDirectoryInput: []string{"?1?1?1"},
+	}, opts)
+
+	opts, err = ParseOptions("-a 1 -r best64.rule -r toggles1.rule --veracrypt-keyfiles=a.png,b.png hash.txt left.txt right.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"best64.rule", "toggles1.rule"}, opts.RulesFile)
+	assert.Equal(t, []string{"a.png", "b.png"}, opts.VeraCryptKeyFiles)
+	assert.Equal(t, []string{"left.txt", "right.txt"}, opts.DictionaryMaskDirectoryInput)
+
+	opts, err = ParseOptions("-m 0 -- -starts-with-a-dash")
+	require.NoError(t, err)
+	assert.Equal(t, "-starts-with-a-dash", opts.InputFile)
+
+	for _, line := range []string{
+		"--invalid-option",
+		"-X",
+		"-m",
+		"--hash-type",
+		"-m abc",
+		"--force=maybe",
+		`-r "unterminated`,
+	} {
+		opts, err := ParseOptions(line)
+		assert.Error(t, err, line)
+		assert.Nil(t, opts, line)
+	}
+}
+
+func TestUnmarshalArgs(t *testing.T) {
+	opts := HashcatSessionOptions{
+		HashType:                     GetIntPtr(1000),
+		AttackMode:                   GetAttackModePtr(AttackHybridDictMask),
+		WorkloadProfile:              GetWorkloadProfilePtr(WorkloadNightmare),
+		OptimizedKernelEnabled:       GetBoolPtr(true),
+		SessionName:                  GetStringPtr("round trip"),
+		CustomCharset1:               GetStringPtr("?l?d"),
+		RulesFile:                    []string{"best64.rule", "toggles1.rule"},
+		TrueCryptKeyFiles:            []string{"a.png", "b.png"},
+		OutfileFormat:                []OutfileFormat{OutfileHash, OutfilePlain},
+		DebugMode:                    GetDebugModePtr(DebugFindingRule),
+		InputFile:                    "hashes.txt",
+		DictionaryMaskDirectoryInput: []string{"rockyou.txt", "?1?1"},
+	}
+
+	args, err := opts.MarshalArgs()
+	require.NoError(t, err)
+
+	unmarshaled, err := UnmarshalArgs(args)
+	require.NoError(t, err)
+	assert.Equal(t, &opts, unmarshaled)
+
+	// arguments as they're stored in a .restore file, starting with the program that ran the session
+	unmarshaled, err = UnmarshalOptions{SkipProgram: true}.Unmarshal([]string{"/opt/cracker/bin/worker", "--session=unittest_example", "-a", "3", "-m", "0", "multi_hashes", "default.hcmask"})
+	require.NoError(t, err)
+	assert.Equal(t, &HashcatSessionOptions{
+		SessionName:                  GetStringPtr("unittest_example"),
+		AttackMode:                   GetAttackModePtr(AttackBruteForce),
+		HashType:                     GetIntPtr(0),
+		InputFile:                    "multi_hashes",
+		DictionaryMaskDirectoryInput: []string{"default.hcmask"},
+	}, unmarshaled)
+
+	// arguments of a keyspace or candidate generation session don't have an InputFile
+	noInputFile := HashcatSessionOptions{
+		AttackMode:                   GetAttackModePtr(AttackCombinator),
+		DictionaryMaskDirectoryInput: []string{"left.txt", "right.txt"},
+	}
+
+	args, err = noInputFile.MarshalArgs()
+	require.NoError(t, err)
+
+	unmarshaled, err = UnmarshalOptions{NoInputFile: true}.Unmarshal(args)
+	require.NoError(t, err)
+	assert.Equal(t, &noInputFile, unmarshaled)
+
+	unmarshaled, err = UnmarshalArgs([]string{"--hash-type", "0", "--unknown", "-XO", "--also-unknown=1", "hashes.txt"})
+	assert.Nil(t, unmarshaled)
+
+	var unknownErr *UnknownArgsError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, []string{"--unknown", "-X", "--also-unknown"}, unknownErr.Args)
+	assert.EqualError(t, err, "invalid arguments: --unknown, -X, --also-unknown")
+}
@@ -0,0 +1,293 @@
+package hcargp
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/niall-san/gocat/v7/types"
+)
+
+// FieldError describes a problem with a single field of HashcatSessionOptions
+type FieldError struct {
+	// Field is the name of the field in HashcatSessionOptions
+	Field string
+	// Flag is the hashcat flag of the field. It's empty for the positional InputFile and DictionaryMaskDirectoryInput fields
+	Flag    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Flag == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", e.Field, e.Flag, e.Message)
+}
+
+// ValidationError is returned by HashcatSessionOptions.Validate and contains every problem that was found
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "invalid options: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns every FieldError so they can be inspected with errors.As
+func (e ValidationError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// attackPositionals describes the positional inputs expected by an attack mode
+type attackPositionals struct {
+	min, max int
+	// files lists the positions (within DictionaryMaskDirectoryInput) that must be a wordlist or directory
+	files []int
+	// rules is set for attack modes that accept -r and -g
+	rules bool
+	// increment is set for attack modes that accept --increment
+	increment bool
+}
+
+var attackModes = map[AttackMode]attackPositionals{
+	// straight reads candidates from stdin when no wordlist is given
+	AttackStraight:       {min: 0, max: -1, files: []int{-1}, rules: true},
+	AttackCombinator:     {min: 2, max: 2, files: []int{0, 1}},
+	AttackBruteForce:     {min: 0, max: 1, increment: true},
+	AttackHybridDictMask: {min: 2, max: 2, files: []int{0}, increment: true},
+	AttackHybridMaskDict: {min: 2, max: 2, files: []int{1}, increment: true},
+	AttackAssociation:    {min: 1, max: 1, files: []int{0}, rules: true},
+}
+
+var (
+	supportedHashesOnce sync.Once
+	supportedHashes     map[int]bool
+)
+
+func isSupportedHash(hashType int) bool {
+	supportedHashesOnce.Do(func() {
+		supportedHashes = make(map[int]bool)
+		for _, hash := range types.SupportedHashes() {
+			supportedHashes[hash.Type] = true
+		}
+	})
+	return supportedHashes[hashType]
+}
+
+// restoreOptions can be combined with --restore. hashcat reads everything else from the restore file
+var restoreOptions = map[string]bool{
+	"RestoreSession":  true,
+	"SessionName":     true,
+	"RestoreFilePath": true,
+}
+
+type validator struct {
+	opts HashcatSessionOptions
+	// stdout is set when the options are used to generate candidates, which doesn't need any hashes
+	stdout bool
+	errs   ValidationError
+}
+
+func (v *validator) addError(field, format string, args ...interface{}) {
+	flag := ""
+	if sf, ok := reflect.TypeOf(v.opts).FieldByName(field); ok {
+		flag, _ = parseTag(sf.Tag.Get("hashcat"))
+	}
+
+	v.errs = append(v.errs, FieldError{
+		Field:   field,
+		Flag:    flag,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// requireFile adds an error to field if path doesn't exist
+func (v *validator) requireFile(field, path string) {
+	if _, err := os.Stat(path); err != nil {
+		v.addError(field, "%s does not exist", path)
+	}
+}
+
+// Validate checks the options for problems that would otherwise only be reported by hashcat once a session is initialized.
+// It returns nil or a ValidationError containing a FieldError for every problem found
+func (o HashcatSessionOptions) Validate() error {
+	v := &validator{opts: o}
+	return v.validate()
+}
+
+// ValidateCandidates is like Validate but checks the options for generating candidates with --stdout, as done by
+// gocat.GenerateCandidates. InputFile is ignored as no hashes are needed and OutfilePath can't be set because
+// the candidates are written to stdout. A straight attack needs a wordlist as it would otherwise read from stdin
+func (o HashcatSessionOptions) ValidateCandidates() error {
+	v := &validator{opts: o, stdout: true}
+	return v.validate()
+}
+
+func (v *validator) validate() error {
+	switch {
+	case v.opts.RestoreSession != nil && *v.opts.RestoreSession && v.stdout:
+		v.addError("RestoreSession", "cannot be combined with --stdout")
+	case v.opts.RestoreSession != nil && *v.opts.RestoreSession:
+		v.validateRestore()
+	default:
+		v.validateHashType()
+		v.validateAttack()
+		v.validateIncrement()
+		v.validateFiles()
+	}
+
+	if v.stdout && v.opts.OutfilePath != nil {
+		v.addError("OutfilePath", "cannot be combined with --stdout")
+	}
+
+	if v.opts.PotfileDisable != nil && *v.opts.PotfileDisable && v.opts.PotfilePath != nil {
+		v.addError("PotfilePath", "cannot be combined with --potfile-disable")
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// validateRestore ensures nothing but the restore options are set when restoring a session
+func (v *validator) validateRestore() {
+	val := reflect.ValueOf(v.opts)
+	for i := 0; i < val.NumField(); i++ {
+		name := val.Type().Field(i).Name
+		if restoreOptions[name] || val.Field(i).IsZero() {
+			continue
+		}
+		// PotfileDisable/PotfilePath are checked separately
+		if name == "PotfileDisable" || name == "PotfilePath" {
+			continue
+		}
+		v.addError(name, "cannot be combined with --restore")
+	}
+
+	if v.opts.RestoreFilePath != nil {
+		v.requireFile("RestoreFilePath", *v.opts.RestoreFilePath)
+	}
+}
+
+func (v *validator) validateHashType() {
+	if v.opts.HashType != nil && !isSupportedHash(*v.opts.HashType) {
+		v.addError("HashType", "%d is not a supported hash type", *v.opts.HashType)
+	}
+}
+
+func (v *validator) attackMode() AttackMode {
+	if v.opts.AttackMode == nil {
+		return AttackStraight
+	}
+	return *v.opts.AttackMode
+}
+
+func (v *validator) validateAttack() {
+	if v.opts.InputFile == "" && !v.stdout {
+		v.addError("InputFile", "a hash or hashfile is required")
+	}
+
+	mode := v.attackMode()
+	attack, ok := attackModes[mode]
+	if !ok {
+		v.addError("AttackMode", "%d is not a valid attack mode", mode)
+		return
+	}
+
+	inputs := v.opts.DictionaryMaskDirectoryInput
+	if v.stdout && mode == AttackStraight && len(inputs) == 0 {
+		// hashcat would read the candidates to generate from its own stdin
+		v.addError("DictionaryMaskDirectoryInput", "attack mode %d requires a wordlist with --stdout", mode)
+	}
+
+	switch {
+	case len(inputs) < attack.min && attack.min == attack.max:
+		v.addError("DictionaryMaskDirectoryInput", "attack mode %d requires %d input(s), got %d", mode, attack.min, len(inputs))
+	case len(inputs) < attack.min:
+		v.addError("DictionaryMaskDirectoryInput", "attack mode %d requires at least %d input(s), got %d", mode, attack.min, len(inputs))
+	case attack.max != -1 && len(inputs) > attack.max:
+		v.addError("DictionaryMaskDirectoryInput", "attack mode %d accepts at most %d input(s), got %d", mode, attack.max, len(inputs))
+	default:
+		for _, pos := range attack.files {
+			if pos == -1 {
+				for _, input := range inputs {
+					v.requireFile("DictionaryMaskDirectoryInput", input)
+				}
+			} else if pos < len(inputs) {
+				v.requireFile("DictionaryMaskDirectoryInput", inputs[pos])
+			}
+		}
+
+		// masks can also be read from a .hcmask file
+		for _, input := range inputs {
+			if strings.HasSuffix(input, ".hcmask") {
+				v.requireFile("DictionaryMaskDirectoryInput", input)
+			}
+		}
+	}
+
+	if !attack.rules {
+		if len(v.opts.RulesFile) > 0 {
+			v.addError("RulesFile", "rules files can't be used with attack mode %d", mode)
+		}
+		if v.opts.GenerateRules != nil {
+			v.addError("GenerateRules", "generated rules can't be used with attack mode %d", mode)
+		}
+	}
+
+	if !attack.increment && v.opts.IncrementMask != nil && *v.opts.IncrementMask {
+		v.addError("IncrementMask", "increment can't be used with attack mode %d", mode)
+	}
+}
+
+func (v *validator) validateIncrement() {
+	increment := v.opts.IncrementMask != nil && *v.opts.IncrementMask
+
+	if v.opts.IncrementMaskMin != nil {
+		if !increment {
+			v.addError("IncrementMaskMin", "requires --increment")
+		} else if *v.opts.IncrementMaskMin < 1 {
+			v.addError("IncrementMaskMin", "must be at least 1")
+		}
+	}
+
+	if v.opts.IncrementMaskMax != nil && !increment {
+		v.addError("IncrementMaskMax", "requires --increment")
+	}
+
+	if increment && v.opts.IncrementMaskMin != nil && v.opts.IncrementMaskMax != nil && *v.opts.IncrementMaskMin > *v.opts.IncrementMaskMax {
+		v.addError("IncrementMaskMin", "must be less than or equal to --increment-max (%d)", *v.opts.IncrementMaskMax)
+	}
+}
+
+// validateFiles checks the options that must point to an existing file
+func (v *validator) validateFiles() {
+	for _, path := range v.opts.RulesFile {
+		v.requireFile("RulesFile", path)
+	}
+
+	for _, path := range v.opts.TrueCryptKeyFiles {
+		v.requireFile("TrueCryptKeyFiles", path)
+	}
+
+	for _, path := range v.opts.VeraCryptKeyFiles {
+		v.requireFile("VeraCryptKeyFiles", path)
+	}
+
+	if v.opts.MarkovHCStat2 != nil {
+		v.requireFile("MarkovHCStat2", *v.opts.MarkovHCStat2)
+	}
+
+	if v.opts.KeyboardLayoutMapping != nil {
+		v.requireFile("KeyboardLayoutMapping", *v.opts.KeyboardLayoutMapping)
+	}
+}
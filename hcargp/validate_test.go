@@ -0,0 +1,233 @@
+package hcargp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := filepath.Join(dir, "words.txt")
+	rules := filepath.Join(dir, "best64.rule")
+	for _, path := range []string{wordlist, rules} {
+		require.NoError(t, os.WriteFile(path, []byte("test\n"), 0600))
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	for _, test := range []struct {
+		name   string
+		opts   HashcatSessionOptions
+		fields []string
+	}{
+		{
+			name: "straight",
+			opts: HashcatSessionOptions{
+				HashType:                     GetIntPtr(0),
+				InputFile:                    "5f4dcc3b5aa765d61d8327deb882cf99",
+				DictionaryMaskDirectoryInput: []string{wordlist, dir},
+				RulesFile:                    []string{rules},
+			},
+		},
+		{
+			name: "straight from stdin",
+			opts: HashcatSessionOptions{InputFile: "hashes.txt"},
+		},
+		{
+			name: "brute force with increment",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackBruteForce),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{"?d?d?d?d"},
+				IncrementMask:                GetBoolPtr(true),
+				IncrementMaskMin:             GetIntPtr(2),
+				IncrementMaskMax:             GetIntPtr(4),
+			},
+		},
+		{
+			name: "hybrid mask dict",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackHybridMaskDict),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{"?d?d", wordlist},
+			},
+		},
+		{
+			name: "restore",
+			opts: HashcatSessionOptions{
+				RestoreSession: GetBoolPtr(true),
+				SessionName:    GetStringPtr("test"),
+			},
+		},
+		{
+			name: "missing hash and unsupported hash type",
+			opts: HashcatSessionOptions{
+				HashType:                     GetIntPtr(123456),
+				DictionaryMaskDirectoryInput: []string{wordlist},
+			},
+			fields: []string{"HashType", "InputFile"},
+		},
+		{
+			name: "invalid attack mode",
+			opts: HashcatSessionOptions{
+				AttackMode: GetAttackModePtr(2),
+				InputFile:  "hashes.txt",
+			},
+			fields: []string{"AttackMode"},
+		},
+		{
+			name: "combinator requires two wordlists",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackCombinator),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{wordlist},
+			},
+			fields: []string{"DictionaryMaskDirectoryInput"},
+		},
+		{
+			name: "brute force accepts one mask",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackBruteForce),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{"?d", "?l"},
+			},
+			fields: []string{"DictionaryMaskDirectoryInput"},
+		},
+		{
+			name: "missing files",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackHybridDictMask),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{missing, "?d?d"},
+				MarkovHCStat2:                GetStringPtr(missing),
+				VeraCryptKeyFiles:            []string{wordlist, missing},
+			},
+			fields: []string{"DictionaryMaskDirectoryInput", "MarkovHCStat2", "VeraCryptKeyFiles"},
+		},
+		{
+			name: "rules and increment outside their attack modes",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackCombinator),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{wordlist, wordlist},
+				RulesFile:                    []string{rules},
+				GenerateRules:                GetIntPtr(100),
+				IncrementMask:                GetBoolPtr(true),
+			},
+			fields: []string{"RulesFile", "GenerateRules", "IncrementMask"},
+		},
+		{
+			name: "increment bounds",
+			opts: HashcatSessionOptions{
+				AttackMode:                   GetAttackModePtr(AttackBruteForce),
+				InputFile:                    "hashes.txt",
+				DictionaryMaskDirectoryInput: []string{"?d?d?d?d"},
+				IncrementMask:                GetBoolPtr(true),
+				IncrementMaskMin:             GetIntPtr(5),
+				IncrementMaskMax:             GetIntPtr(3),
+			},
+			fields: []string{"IncrementMaskMin"},
+		},
+		{
+			name: "increment bounds without increment",
+			opts: HashcatSessionOptions{
+				AttackMode:       GetAttackModePtr(AttackBruteForce),
+				InputFile:        "hashes.txt",
+				IncrementMaskMin: GetIntPtr(1),
+				IncrementMaskMax: GetIntPtr(3),
+			},
+			fields: []string{"IncrementMaskMin", "IncrementMaskMax"},
+		},
+		{
+			name: "restore with session options",
+			opts: HashcatSessionOptions{
+				RestoreSession:  GetBoolPtr(true),
+				AttackMode:      GetAttackModePtr(AttackBruteForce),
+				InputFile:       "hashes.txt",
+				RestoreFilePath: GetStringPtr(missing),
+			},
+			fields: []string{"AttackMode", "InputFile", "RestoreFilePath"},
+		},
+		{
+			name: "potfile path with potfile disabled",
+			opts: HashcatSessionOptions{
+				InputFile:      "hashes.txt",
+				PotfileDisable: GetBoolPtr(true),
+				PotfilePath:    GetStringPtr("hashcat.potfile"),
+			},
+			fields: []string{"PotfilePath"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.opts.Validate()
+			if len(test.fields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr ValidationError
+			require.True(t, errors.As(err, &verr), "expected a ValidationError, got %v", err)
+
+			fields := []string{}
+			for _, fe := range verr {
+				fields = append(fields, fe.Field)
+			}
+			assert.ElementsMatch(t, test.fields, uniqueStrings(fields))
+		})
+	}
+}
+
+func TestValidationErrorFields(t *testing.T) {
+	err := HashcatSessionOptions{
+		HashType:  GetIntPtr(123456),
+		InputFile: "hashes.txt",
+	}.Validate()
+
+	var fe FieldError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, "HashType", fe.Field)
+	assert.Equal(t, "--hash-type", fe.Flag)
+	assert.Equal(t, "invalid options: HashType (--hash-type): 123456 is not a supported hash type", err.Error())
+}
+
+func TestValidateCandidates(t *testing.T) {
+	opts := HashcatSessionOptions{
+		AttackMode:                   GetAttackModePtr(AttackBruteForce),
+		DictionaryMaskDirectoryInput: []string{"?d?d"},
+	}
+	require.NoError(t, opts.ValidateCandidates())
+
+	opts.OutfilePath = GetStringPtr("candidates.txt")
+	err := opts.ValidateCandidates()
+
+	var fe FieldError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, "OutfilePath", fe.Field)
+	assert.Equal(t, "invalid options: OutfilePath (--outfile): cannot be combined with --stdout", err.Error())
+
+	err = HashcatSessionOptions{RestoreSession: GetBoolPtr(true)}.ValidateCandidates()
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, "RestoreSession", fe.Field)
+
+	// a straight attack without a wordlist reads from stdin
+	err = HashcatSessionOptions{AttackMode: GetAttackModePtr(AttackStraight)}.ValidateCandidates()
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, "DictionaryMaskDirectoryInput", fe.Field)
+	require.NoError(t, HashcatSessionOptions{InputFile: "hashes.txt"}.Validate(), "only generating candidates requires a wordlist")
+}
+
+func uniqueStrings(in []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
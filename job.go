@@ -0,0 +1,335 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// SessionOutcome describes how a hashcat session ended
+type SessionOutcome int
+
+const (
+	// OutcomeUnknown indicates hashcat finished the session in a state gocat doesn't recognize
+	OutcomeUnknown SessionOutcome = iota
+	// OutcomeCracked indicates all hashes were cracked (either during the session or from the potfile)
+	OutcomeCracked
+	// OutcomeExhausted indicates all possible permutations were reached in the session
+	OutcomeExhausted
+	// OutcomeQuit indicates the session was quit by the user
+	OutcomeQuit
+	// OutcomeAborted indicates the session was aborted by the user or by hashcat
+	OutcomeAborted
+	// OutcomeAbortedCheckpoint indicates the session was stopped at a checkpoint (usually due to an abort signal or temperature limit)
+	OutcomeAbortedCheckpoint
+	// OutcomeAbortedRuntime indicates the session was stopped by hashcat because it reached its runtime limit
+	OutcomeAbortedRuntime
+)
+
+func (o SessionOutcome) String() string {
+	switch o {
+	case OutcomeCracked:
+		return "Cracked"
+	case OutcomeExhausted:
+		return "Exhausted"
+	case OutcomeQuit:
+		return "Quit"
+	case OutcomeAborted:
+		return "Aborted"
+	case OutcomeAbortedCheckpoint:
+		return "Aborted (Checkpoint)"
+	case OutcomeAbortedRuntime:
+		return "Aborted (Runtime)"
+	default:
+		return "Unknown"
+	}
+}
+
+// Job describes a single hashcat session started with Hashcat.Run
+type Job struct {
+	// Args are the raw command line arguments passed into hashcat. Args is ignored when Options is set
+	Args []string
+	// Options are the session options passed into hashcat
+	Options *hcargp.HashcatSessionOptions
+	// Candidates if set is piped into hashcat's stdin as the wordlist of a straight attack (-a 0), with or without rules.
+	// Each line is a candidate, see CandidatesFromChan to feed candidates from a channel. When Options is set,
+	// DictionaryMaskDirectoryInput is ignored. Only one session in the process can read candidates at a time
+	// NOTE: this only works on posix systems (linux, darwin)
+	Candidates io.Reader
+	// Hashes if set are the hashes to crack, one per entry. gocat writes them into a private temporary file which is
+	// removed once the session ends. Hashes requires Options to be set and InputFile to be empty.
+	// CrackedPayload.Original is set to the entry a cracked hash came from
+	Hashes []string
+}
+
+// ErrCandidatesAttackMode is raised when Job.Candidates is used with an attack mode other than straight (-a 0)
+var ErrCandidatesAttackMode = errors.New("candidates can only be used with a straight attack (-a 0)")
+
+func (j Job) args() ([]string, error) {
+	if j.Options == nil {
+		return j.Args, nil
+	}
+
+	opts := *j.Options
+	if j.Candidates != nil {
+		if opts.AttackMode != nil && *opts.AttackMode != hcargp.AttackStraight {
+			return nil, ErrCandidatesAttackMode
+		}
+		// hashcat reads from stdin when a straight attack is given no wordlist
+		opts.DictionaryMaskDirectoryInput = nil
+	}
+	return opts.MarshalArgs()
+}
+
+// prepare returns the arguments for hashcat. When Hashes is set they're written into a temporary file
+// which is removed by cleanup. cleanup is always safe to call
+func (j Job) prepare() (args []string, cleanup func(), err error) {
+	cleanup = func() {}
+	if len(j.Hashes) == 0 {
+		args, err = j.args()
+		return args, cleanup, err
+	}
+
+	if j.Options == nil {
+		return nil, cleanup, ErrHashesRequireOptions
+	}
+
+	if j.Options.InputFile != "" {
+		return nil, cleanup, ErrHashesWithInputFile
+	}
+
+	path, cleanup, err := writeHashesFile(j.Hashes)
+	if err != nil {
+		return nil, cleanup, err
+	}
+
+	opts := *j.Options
+	opts.InputFile = path
+	j.Options = &opts
+
+	args, err = j.args()
+	return args, cleanup, err
+}
+
+// originalHashes indexes Hashes so cracks can be mapped back to them
+func (j Job) originalHashes() *originalHashes {
+	if len(j.Hashes) == 0 || j.Options == nil {
+		return nil
+	}
+
+	sep, usernames := j.hashFormat()
+	return newOriginalHashes(j.Hashes, sep, usernames)
+}
+
+// hashFormat returns the separator used in Hashes and whether they're prefixed with a username (--username)
+func (j Job) hashFormat() (sep string, usernames bool) {
+	sep = ":"
+	if j.Options.Separator != nil {
+		sep = *j.Options.Separator
+	}
+	return sep, j.Options.IgnoreUsername != nil && *j.Options.IgnoreUsername
+}
+
+// JobResult contains the outcome of a finished hashcat session
+type JobResult struct {
+	Outcome   SessionOutcome
+	StartedAt time.Time
+	EndedAt   time.Time
+	// Status is the final status of the session. This will be nil if hashcat never started cracking
+	// (for example, when every hash was already in the potfile)
+	Status *Status
+	// Snapshot is the final status of the session as raw numbers. Like Status, this can be nil
+	Snapshot *StatusSnapshot
+	// Cracked is the number of hashes cracked during the session
+	Cracked int
+	// PotfileHits is the number of hashes that were already cracked in the potfile
+	PotfileHits int
+	// RecoveryStalled is set when the session was stopped because of Options.RecoveryThreshold
+	RecoveryStalled bool
+}
+
+// maxParseErrors limits how many rejected hashes are kept for a session
+const maxParseErrors = 100
+
+// jobState tracks the session started by Hashcat.run. It's updated by the event callback from hashcat's threads
+type jobState struct {
+	// hook is called for every event fired by hashcat during the session. It's used by the special
+	// hashcat modes (--keyspace, --benchmark, etc) to grab their results out of hashcat_ctx
+	hook func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer)
+	// initOnly, when set, is called once the session has been initialized and the session is not executed.
+	// This mirrors how hashcat's main.c handles modes such as --backend-info
+	initOnly func(hcCtx *C.hashcat_ctx_t)
+
+	// history is set by Hashcat.run before the session starts
+	history *recoveryHistory
+	// originals is set when the session was started with Job.Hashes
+	originals *originalHashes
+	// potfileStore is set when the session uses Options.PotfileStore instead of hashcat's potfile
+	potfileStore bool
+
+	mu                sync.Mutex
+	result            JobResult
+	allCracked        bool
+	parseErrors       []error
+	crackingStartedAt time.Time
+}
+
+func newJobState() *jobState {
+	return &jobState{
+		result: JobResult{
+			StartedAt: time.Now().UTC(),
+		},
+	}
+}
+
+func (j *jobState) addCracked() {
+	j.history.add(time.Now())
+
+	j.mu.Lock()
+	j.result.Cracked++
+	j.mu.Unlock()
+}
+
+// originalHash returns the entry of Job.Hashes that hash came from
+func (j *jobState) originalHash(hash string) string {
+	if j.originals == nil {
+		return ""
+	}
+	return j.originals.lookup(hash)
+}
+
+// markCrackingStarted records when hashcat first started cracking. RecoveryThreshold.Window is measured from here
+func (j *jobState) markCrackingStarted() {
+	j.mu.Lock()
+	if j.crackingStartedAt.IsZero() {
+		j.crackingStartedAt = time.Now()
+	}
+	j.mu.Unlock()
+}
+
+// restartRecoveryWindow measures RecoveryThreshold.Window from now. It's called when a paused session is resumed
+// so the time spent paused doesn't count towards the window
+func (j *jobState) restartRecoveryWindow() {
+	j.mu.Lock()
+	if !j.crackingStartedAt.IsZero() {
+		j.crackingStartedAt = time.Now()
+	}
+	j.mu.Unlock()
+}
+
+// recoveryStalled returns the number of cracks within threshold.Window and whether that is below the threshold
+func (j *jobState) recoveryStalled(threshold RecoveryThreshold, now time.Time) (int, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.crackingStartedAt.IsZero() || now.Sub(j.crackingStartedAt) < threshold.Window {
+		return 0, false
+	}
+
+	cracks := j.history.count(now, threshold.Window)
+	return cracks, cracks < threshold.MinCracks
+}
+
+// markRecoveryStalled flags the job as stopped because of Options.RecoveryThreshold
+func (j *jobState) markRecoveryStalled() {
+	j.mu.Lock()
+	j.result.RecoveryStalled = true
+	j.mu.Unlock()
+}
+
+func (j *jobState) setPotfileHits(n int, allCracked bool) {
+	j.mu.Lock()
+	j.result.PotfileHits = n
+	j.allCracked = j.allCracked || allCracked
+	j.mu.Unlock()
+}
+
+func (j *jobState) setFinalStatus(status *Status, snapshot *StatusSnapshot) {
+	j.mu.Lock()
+	j.result.Status = status
+	j.result.Snapshot = snapshot
+	j.mu.Unlock()
+}
+
+func (j *jobState) addParseError(pe *HashParseError) {
+	j.mu.Lock()
+	if len(j.parseErrors) < maxParseErrors {
+		j.parseErrors = append(j.parseErrors, pe)
+	}
+	j.mu.Unlock()
+}
+
+// sessionError attaches the hashes rejected by hashcat to err when the session failed because no hashes could be loaded
+func (j *jobState) sessionError(err error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var hcErr *HashcatError
+	if len(j.parseErrors) > 0 && errors.As(err, &hcErr) && errors.Is(hcErr.Err, ErrNoHashesLoaded) {
+		hcErr.Err = errors.Join(append([]error{hcErr.Err}, j.parseErrors...)...)
+	}
+	return err
+}
+
+// finish fills in the outcome of the session using hashcat's device status and the return code of hashcat_session_execute
+func (j *jobState) finish(devicesStatus C.u32, rc int) *JobResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.result.EndedAt = time.Now().UTC()
+	j.result.Outcome = outcomeFromDevicesStatus(devicesStatus)
+	if j.result.Outcome == OutcomeUnknown {
+		j.result.Outcome = outcomeFromReturnCode(rc)
+	}
+
+	if j.allCracked {
+		j.result.Outcome = OutcomeCracked
+	}
+
+	res := j.result
+	return &res
+}
+
+func outcomeFromDevicesStatus(status C.u32) SessionOutcome {
+	switch status {
+	case C.STATUS_CRACKED:
+		return OutcomeCracked
+	case C.STATUS_EXHAUSTED:
+		return OutcomeExhausted
+	case C.STATUS_QUIT:
+		return OutcomeQuit
+	case C.STATUS_ABORTED:
+		return OutcomeAborted
+	case C.STATUS_ABORTED_CHECKPOINT:
+		return OutcomeAbortedCheckpoint
+	case C.STATUS_ABORTED_RUNTIME:
+		return OutcomeAbortedRuntime
+	default:
+		return OutcomeUnknown
+	}
+}
+
+func outcomeFromReturnCode(rc int) SessionOutcome {
+	switch rc {
+	case sessionCracked:
+		return OutcomeCracked
+	case sessionExhausted:
+		return OutcomeExhausted
+	case sessionQuit:
+		return OutcomeQuit
+	case sessionAborted:
+		return OutcomeAborted
+	case sessionAbortedCheckpoint:
+		return OutcomeAbortedCheckpoint
+	case sessionAbortedRuntime:
+		return OutcomeAbortedRuntime
+	default:
+		return OutcomeUnknown
+	}
+}
@@ -0,0 +1,41 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"unsafe"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+)
+
+// Keyspace calculates the keyspace of the attack described by opts using hashcat's --keyspace mode.
+// The returned value is in the same unit hashcat uses for --skip and --limit, which makes it suitable for splitting
+// an attack into chunks. opts.InputFile is ignored as no hashes are needed to calculate the keyspace
+func (hc *Hashcat) Keyspace(opts hcargp.HashcatSessionOptions) (uint64, error) {
+	opts.InputFile = ""
+
+	args, err := opts.MarshalArgs()
+	if err != nil {
+		return 0, err
+	}
+
+	var keyspace uint64
+	var found bool
+
+	job := newJobState()
+	job.hook = func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) {
+		if id == C.EVENT_CALCULATED_WORDS_BASE && hcCtx != nil && hcCtx.user_options.keyspace {
+			keyspace = uint64(hcCtx.status_ctx.words_base)
+			found = true
+		}
+	}
+
+	if _, err := hc.run(append(args, "--keyspace"), job); err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, ErrNoKeyspace
+	}
+	return keyspace, nil
+}
@@ -0,0 +1,148 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"fmt"
+	"time"
+)
+
+// sessionMonitor runs while hashcat's outer loop is running. It periodically emits a StatusPayload
+// and stops the session once Options.RecoveryThreshold is no longer met
+type sessionMonitor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startMonitor starts monitoring the running session. It's a no-op if neither StatusInterval nor
+// RecoveryThreshold are set or a monitor is already running
+func (hc *Hashcat) startMonitor() {
+	if hc.opts.StatusInterval <= 0 && !hc.opts.RecoveryThreshold.enabled() {
+		return
+	}
+
+	m := &sessionMonitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if !hc.monitor.CompareAndSwap(nil, m) {
+		return
+	}
+
+	go hc.runMonitor(m)
+}
+
+// stopMonitor stops the running monitor and waits for it to exit so no StatusPayload can be
+// emitted after it returns
+func (hc *Hashcat) stopMonitor() {
+	m := hc.monitor.Swap(nil)
+	if m == nil {
+		return
+	}
+
+	close(m.stop)
+	<-m.done
+}
+
+// runMonitor runs on its own goroutine rather than one of hashcat's threads which means it never holds
+// hashcat's event mutex. This keeps it safe to use alongside Options.PatchEventContext
+func (hc *Hashcat) runMonitor(m *sessionMonitor) {
+	defer close(m.done)
+
+	var statusC, thresholdC <-chan time.Time
+	if hc.opts.StatusInterval > 0 {
+		ticker := time.NewTicker(hc.opts.StatusInterval)
+		defer ticker.Stop()
+		statusC = ticker.C
+	}
+
+	if threshold := hc.opts.RecoveryThreshold; threshold.enabled() {
+		ticker := time.NewTicker(threshold.checkInterval())
+		defer ticker.Stop()
+		thresholdC = ticker.C
+	}
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-statusC:
+			hc.emitStatus(m)
+		case <-thresholdC:
+			if hc.checkRecoveryThreshold() {
+				thresholdC = nil
+			}
+		}
+	}
+}
+
+func (hc *Hashcat) emitStatus(m *sessionMonitor) {
+	var payload StatusPayload
+	ok := readStatus(&hc.wrapper.ctx, func(hcStatus *C.hashcat_status_t) {
+		// hwmon is read once and shared by both representations
+		hwmon := hwmonFromC(hcStatus)
+		payload.Status = statusFromC(hcStatus, hwmon)
+		payload.Snapshot = snapshotFromC(&hc.wrapper.ctx, hcStatus, hwmon)
+	})
+	if !ok {
+		return
+	}
+
+	// stop may have been requested while we were reading the status
+	select {
+	case <-m.stop:
+		return
+	default:
+	}
+
+	rate := hc.recoveryRate()
+	payload.Status.RecoveryRate = rate
+	payload.Snapshot.RecoveryRate = rate
+
+	hc.emit(&hc.wrapper.ctx, 0, payload)
+}
+
+// checkRecoveryThreshold stops the session if it isn't cracking enough hashes and returns true if it did.
+// Only a running session is checked, nothing is cracked while it's paused or autotuning between outer loops
+func (hc *Hashcat) checkRecoveryThreshold() bool {
+	job := hc.job.Load()
+	if job == nil || hc.State() != StateRunning {
+		return false
+	}
+
+	threshold := hc.opts.RecoveryThreshold
+	cracks, stalled := job.recoveryStalled(threshold, time.Now())
+	if !stalled {
+		return false
+	}
+
+	if err := hc.StopAtCheckpoint(); err != nil {
+		if !hc.restoreDisabled() {
+			// the session was paused or left StateRunning since we checked, try again later
+			return false
+		}
+		// hashcat can't stop at a checkpoint when --restore-disable is set
+		hc.AbortRunningTask()
+	}
+
+	job.markRecoveryStalled()
+	hc.emit(&hc.wrapper.ctx, 0, LogPayload{
+		Level:   WarnMessage,
+		Message: fmt.Sprintf("Stopping session, only %d hash(es) cracked in the last %s", cracks, threshold.Window),
+	})
+	return true
+}
+
+// restoreDisabled reports if the running session was started with --restore-disable
+func (hc *Hashcat) restoreDisabled() bool {
+	userOptions := hc.wrapper.ctx.user_options
+	return userOptions != nil && bool(userOptions.restore_disable)
+}
+
+// recoveryRate returns the recovery rate of the running job
+func (hc *Hashcat) recoveryRate() RecoveryRate {
+	if job := hc.job.Load(); job != nil {
+		return job.history.rate(time.Now())
+	}
+	return RecoveryRate{}
+}
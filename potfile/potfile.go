@@ -0,0 +1,352 @@
+// Package potfile reads, writes, and merges hashcat potfiles.
+package potfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSeparator is the separator hashcat uses between a hash and its plaintext
+const DefaultSeparator = ':'
+
+// maxLineSize is the longest potfile line we're willing to read. Some hash modes (7z, pdf, etc) produce very long hashes
+const maxLineSize = 16 * 1024 * 1024
+
+// ErrMissingSeparator is raised when a potfile line doesn't contain enough separators
+var ErrMissingSeparator = errors.New("missing separator")
+
+// Entry is a single cracked hash in a potfile
+type Entry struct {
+	Hash string
+	// Plain is the decoded plaintext of the hash. $HEX[] plaintexts are decoded when read and encoded when written
+	Plain string
+}
+
+// Options controls how potfile lines are split into an Entry
+type Options struct {
+	// Separator is the character between a hash and its plaintext (hashcat's --separator). Defaults to DefaultSeparator
+	Separator byte
+	// HashFields is the number of separator delimited fields that make up a hash. For example, salted hashes
+	// written as hash:salt need 2. When HashFields is zero, the hash is everything before the last separator which
+	// works as long as plaintexts containing the separator are written in $HEX[] notation (hashcat does this by default)
+	HashFields int
+}
+
+func (o Options) separator() byte {
+	if o.Separator == 0 {
+		return DefaultSeparator
+	}
+	return o.Separator
+}
+
+// ParseError is raised when a potfile line can't be parsed
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("potfile: line %d: %s", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader streams the entries of a potfile
+type Reader struct {
+	sc   *bufio.Scanner
+	opts Options
+	line int
+}
+
+// NewReader returns a Reader that reads a potfile from r
+func NewReader(r io.Reader, opts Options) *Reader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	return &Reader{
+		sc:   sc,
+		opts: opts,
+	}
+}
+
+// Read returns the next entry of the potfile. Empty lines are skipped. Read returns io.EOF once there are no entries left
+// and a *ParseError if a line is malformed. Reading can continue after a *ParseError
+func (r *Reader) Read() (Entry, error) {
+	for r.sc.Scan() {
+		r.line++
+
+		line := strings.TrimRight(r.sc.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		entry, err := ParseLine(line, r.opts)
+		if err != nil {
+			return Entry{}, &ParseError{Line: r.line, Err: err}
+		}
+		return entry, nil
+	}
+
+	if err := r.sc.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// ReadAll reads every entry of the potfile
+func (r *Reader) ReadAll() ([]Entry, error) {
+	var entries []Entry
+	for {
+		entry, err := r.Read()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// ReadFile reads every entry of the potfile at path
+func ReadFile(path string, opts Options) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewReader(f, opts).ReadAll()
+}
+
+// ParseLine parses a single potfile line
+func ParseLine(line string, opts Options) (Entry, error) {
+	sep := opts.separator()
+
+	idx := -1
+	if opts.HashFields > 0 {
+		for i, fields := 0, 0; i < len(line); i++ {
+			if line[i] == sep {
+				if fields++; fields == opts.HashFields {
+					idx = i
+					break
+				}
+			}
+		}
+	} else {
+		idx = strings.LastIndexByte(line, sep)
+	}
+
+	if idx == -1 {
+		return Entry{}, ErrMissingSeparator
+	}
+
+	return Entry{
+		Hash:  line[:idx],
+		Plain: DecodePlain(line[idx+1:]),
+	}, nil
+}
+
+// DecodePlain decodes a plaintext written in hashcat's $HEX[] notation. Anything else is returned as is
+func DecodePlain(plain string) string {
+	if !strings.HasPrefix(plain, "$HEX[") || !strings.HasSuffix(plain, "]") {
+		return plain
+	}
+
+	b, err := hex.DecodeString(plain[5 : len(plain)-1])
+	if err != nil {
+		return plain
+	}
+	return string(b)
+}
+
+// EncodePlain encodes plain in hashcat's $HEX[] notation when it can't be written as is. This mirrors hashcat's need_hexify:
+// plaintexts with non-printable characters, the separator, or that could be mistaken for $HEX[] are encoded
+func EncodePlain(plain string, sep byte) string {
+	if sep == 0 {
+		sep = DefaultSeparator
+	}
+
+	needsHex := strings.HasPrefix(plain, "$HEX[")
+	for i := 0; i < len(plain) && !needsHex; i++ {
+		needsHex = plain[i] < 0x20 || plain[i] > 0x7e || plain[i] == sep
+	}
+
+	if needsHex {
+		return "$HEX[" + hex.EncodeToString([]byte(plain)) + "]"
+	}
+	return plain
+}
+
+// Writer writes entries in hashcat's potfile format
+type Writer struct {
+	w   *bufio.Writer
+	sep byte
+}
+
+// NewWriter returns a Writer that writes a potfile into w. Call Flush when you're done writing
+func NewWriter(w io.Writer, opts Options) *Writer {
+	return &Writer{
+		w:   bufio.NewWriter(w),
+		sep: opts.separator(),
+	}
+}
+
+// Write writes entry as a single potfile line
+func (w *Writer) Write(entry Entry) error {
+	if strings.ContainsAny(entry.Hash, "\r\n") {
+		return fmt.Errorf("potfile: hash %q contains a newline", entry.Hash)
+	}
+
+	var line bytes.Buffer
+	line.WriteString(entry.Hash)
+	line.WriteByte(w.sep)
+	line.WriteString(EncodePlain(entry.Plain, w.sep))
+	line.WriteByte('\n')
+
+	_, err := w.w.Write(line.Bytes())
+	return err
+}
+
+// Flush writes any buffered entries into the underlying io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Dedupe returns entries without duplicates, keeping the first occurrence of each hash and plaintext pair.
+// A hash with different plaintexts (a collision) is kept once per plaintext, like hashcat does
+func Dedupe(entries []Entry) []Entry {
+	seen := make(map[Entry]struct{}, len(entries))
+	deduped := entries[:0:0]
+
+	for _, entry := range entries {
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		deduped = append(deduped, entry)
+	}
+	return deduped
+}
+
+// MergeResult describes the outcome of Merge
+type MergeResult struct {
+	// Written is the number of entries written into dst
+	Written int
+	// Skipped contains the lines that couldn't be parsed. They're left out of dst, hashcat ignores them too
+	Skipped []SkippedLine
+}
+
+// SkippedLine is a malformed line found by Merge
+type SkippedLine struct {
+	// Source is the index of the potfile in srcs
+	Source int
+	Err    *ParseError
+}
+
+// Merge streams the potfiles in srcs into dst, dropping duplicate entries. Entries are written in the order they're read.
+// Every unique entry is kept in memory to detect duplicates. Malformed lines are skipped and reported in MergeResult.Skipped
+func Merge(dst io.Writer, opts Options, srcs ...io.Reader) (res MergeResult, err error) {
+	w := NewWriter(dst, opts)
+	seen := make(map[Entry]struct{})
+
+	for i, src := range srcs {
+		r := NewReader(src, opts)
+		for {
+			entry, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				res.Skipped = append(res.Skipped, SkippedLine{Source: i, Err: pe})
+				continue
+			} else if err != nil {
+				return res, err
+			}
+
+			if _, ok := seen[entry]; ok {
+				continue
+			}
+			seen[entry] = struct{}{}
+
+			if err := w.Write(entry); err != nil {
+				return res, err
+			}
+			res.Written++
+		}
+	}
+	return res, w.Flush()
+}
+
+// MergeFiles merges the potfiles at srcs into a potfile at dst, see Merge. The merged potfile is written to a temporary
+// file next to dst which replaces dst once every source has been read, so dst can also be one of srcs.
+// An existing dst keeps its permissions, a new one is created with 0644
+func MergeFiles(dst string, opts Options, srcs ...string) (res MergeResult, err error) {
+	mode := fs.FileMode(0644)
+	if fi, err := os.Stat(dst); err == nil {
+		mode = fi.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return res, err
+	}
+
+	files := make([]*os.File, 0, len(srcs))
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	readers := make([]io.Reader, 0, len(srcs))
+	for _, src := range srcs {
+		f, err := os.Open(src)
+		if err != nil {
+			return res, err
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(out.Name())
+		}
+	}()
+
+	if res, err = Merge(out, opts, readers...); err != nil {
+		return res, err
+	}
+	// CreateTemp always uses 0600
+	if err = out.Chmod(mode); err != nil {
+		return res, err
+	}
+	if err = out.Sync(); err != nil {
+		return res, err
+	}
+	if err = out.Close(); err != nil {
+		return res, err
+	}
+
+	// the sources must be closed before dst can be replaced on windows
+	for _, f := range files {
+		f.Close()
+	}
+	files = nil
+
+	return res, os.Rename(out.Name(), dst)
+}
@@ -0,0 +1,191 @@
+package potfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	entries, err := ReadFile("./testdata/mixed.potfile", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Hash: "5d41402abc4b2a76b9719d911017c592", Plain: "hello"},
+		{Hash: "8c34c7a6a7f6a8cd31d0b7b1e33d5da3:746573746e", Plain: "pass:word"},
+		{Hash: "7d793037a0760186574b0282f2f435e7", Plain: "world"},
+		{Hash: "5d41402abc4b2a76b9719d911017c592", Plain: "hello"},
+	}, entries)
+
+	entries, err = ReadFile("../testdata/two_md5.potfile", Options{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestParseLine(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		opts     Options
+		expected Entry
+		err      error
+	}{
+		{
+			line:     "5d41402abc4b2a76b9719d911017c592:hello",
+			expected: Entry{Hash: "5d41402abc4b2a76b9719d911017c592", Plain: "hello"},
+		},
+		{
+			line:     "hash:salt:pa:ss",
+			opts:     Options{HashFields: 2},
+			expected: Entry{Hash: "hash:salt", Plain: "pa:ss"},
+		},
+		{
+			line:     "hash|salt|$HEX[00ff]",
+			opts:     Options{Separator: '|'},
+			expected: Entry{Hash: "hash|salt", Plain: "\x00\xff"},
+		},
+		{
+			line:     "hash:",
+			expected: Entry{Hash: "hash", Plain: ""},
+		},
+		{
+			line:     "hash:$HEX[zz]",
+			expected: Entry{Hash: "hash", Plain: "$HEX[zz]"},
+		},
+		{
+			line: "nohash",
+			err:  ErrMissingSeparator,
+		},
+		{
+			line: "hash:salt",
+			opts: Options{HashFields: 2},
+			err:  ErrMissingSeparator,
+		},
+	} {
+		entry, err := ParseLine(test.line, test.opts)
+		assert.Equal(t, test.err, err, test.line)
+		assert.Equal(t, test.expected, entry, test.line)
+	}
+}
+
+func TestReaderParseError(t *testing.T) {
+	r := NewReader(strings.NewReader("hash:plain\nbroken\nhash2:plain2\n"), Options{})
+
+	_, err := r.Read()
+	require.NoError(t, err)
+
+	_, err = r.Read()
+	var pe *ParseError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, 2, pe.Line)
+	assert.True(t, errors.Is(err, ErrMissingSeparator))
+
+	entry, err := r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Hash: "hash2", Plain: "plain2"}, entry)
+
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestEncodePlain(t *testing.T) {
+	assert.Equal(t, "hello", EncodePlain("hello", 0))
+	assert.Equal(t, "$HEX[706173733a776f7264]", EncodePlain("pass:word", 0))
+	assert.Equal(t, "pass:word", EncodePlain("pass:word", '|'))
+	assert.Equal(t, "$HEX[d0bfd180d0b8d0b2d0b5d182]", EncodePlain("привет", 0))
+	assert.Equal(t, "$HEX[244845585b5d]", EncodePlain("$HEX[]", 0))
+
+	for _, plain := range []string{"hello", "pass:word", "привет", "$HEX[]", "tab\there"} {
+		assert.Equal(t, plain, DecodePlain(EncodePlain(plain, 0)))
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf, Options{})
+	require.NoError(t, w.Write(Entry{Hash: "hash:salt", Plain: "pass:word"}))
+	require.NoError(t, w.Write(Entry{Hash: "hash2", Plain: "plain"}))
+	assert.Error(t, w.Write(Entry{Hash: "bad\nhash", Plain: "plain"}))
+	require.NoError(t, w.Flush())
+
+	assert.Equal(t, "hash:salt:$HEX[706173733a776f7264]\nhash2:plain\n", buf.String())
+}
+
+func TestDedupe(t *testing.T) {
+	entries := []Entry{
+		{Hash: "a", Plain: "1"},
+		{Hash: "b", Plain: "2"},
+		{Hash: "a", Plain: "1"},
+		{Hash: "a", Plain: "3"},
+	}
+	assert.Equal(t, []Entry{
+		{Hash: "a", Plain: "1"},
+		{Hash: "b", Plain: "2"},
+		{Hash: "a", Plain: "3"},
+	}, Dedupe(entries))
+	assert.Len(t, entries, 4)
+}
+
+func TestMergeFiles(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "merged.potfile")
+
+	res, err := MergeFiles(dst, Options{}, "./testdata/mixed.potfile", "../testdata/two_md5.potfile", "../testdata/one_md5_in_potfile.potfile")
+	require.NoError(t, err)
+	assert.Empty(t, res.Skipped)
+
+	entries, err := ReadFile(dst, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, res.Written, len(entries))
+	assert.Equal(t, Dedupe(entries), entries)
+	assert.Equal(t, Entry{Hash: "8c34c7a6a7f6a8cd31d0b7b1e33d5da3:746573746e", Plain: "pass:word"}, entries[1])
+
+	// merging a potfile into itself must not lose its entries or permissions
+	require.NoError(t, os.Chmod(dst, 0640))
+	res, err = MergeFiles(dst, Options{}, dst, "./testdata/mixed.potfile")
+	require.NoError(t, err)
+	assert.Equal(t, len(entries), res.Written)
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(dst)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+	}
+
+	merged, err := ReadFile(dst, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, entries, merged)
+
+	_, err = MergeFiles(dst, Options{}, "./testdata/does_not_exist.potfile")
+	assert.Error(t, err)
+
+	// a failed merge leaves dst and its directory untouched
+	merged, err = ReadFile(dst, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, entries, merged)
+
+	files, err := os.ReadDir(filepath.Dir(dst))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestMergeSkipsMalformedLines(t *testing.T) {
+	var out bytes.Buffer
+	res, err := Merge(&out, Options{},
+		strings.NewReader("5d41402abc4b2a76b9719d911017c592:hello\n"),
+		strings.NewReader("no separator here\n7d793037a0760186574b0282f2f435e7:world\n"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Written)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592:hello\n7d793037a0760186574b0282f2f435e7:world\n", out.String())
+
+	require.Len(t, res.Skipped, 1)
+	assert.Equal(t, 1, res.Skipped[0].Source)
+	assert.Equal(t, 1, res.Skipped[0].Err.Line)
+	assert.True(t, errors.Is(res.Skipped[0].Err, ErrMissingSeparator))
+}
@@ -0,0 +1,62 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Show returns the hashes from hashfile that have already been cracked in potfile. This is an implementation of --show.
+// hashfile can either be a path to a hashfile or a single hash. If potfile is empty, hashcat's default potfile is used.
+// When hasUsername is set, hashes are expected to be prefixed with a username (--username) which is kept in CrackedPayload.Username.
+// Entries that couldn't be read are left out of the result and their errors are joined into the returned error
+func (hc *Hashcat) Show(hashfile string, hashType int, potfile string, hasUsername bool) ([]CrackedPayload, error) {
+	var cracked []CrackedPayload
+	var errs []error
+
+	err := hc.queryPotfile("--show", hashfile, hashType, potfile, hasUsername, func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) {
+		if id != C.EVENT_POTFILE_HASH_SHOW {
+			return
+		}
+
+		pl, err := crackedPasswordFromEvent(id, hcCtx, buf)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		cracked = append(cracked, pl)
+	})
+	return cracked, errors.Join(append([]error{err}, errs...)...)
+}
+
+// Left returns the hashes from hashfile that haven't been cracked in potfile. This is an implementation of --left.
+// The arguments are the same as Show
+func (hc *Hashcat) Left(hashfile string, hashType int, potfile string, hasUsername bool) ([]LeftPayload, error) {
+	var left []LeftPayload
+
+	err := hc.queryPotfile("--left", hashfile, hashType, potfile, hasUsername, func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer) {
+		if id == C.EVENT_POTFILE_HASH_LEFT {
+			left = append(left, leftHashFromEvent(hcCtx, buf))
+		}
+	})
+	return left, err
+}
+
+func (hc *Hashcat) queryPotfile(mode, hashfile string, hashType int, potfile string, hasUsername bool, hook func(id uint32, hcCtx *C.hashcat_ctx_t, buf unsafe.Pointer)) error {
+	args := []string{mode, fmt.Sprintf("--hash-type=%d", hashType)}
+	if potfile != "" {
+		args = append(args, "--potfile-path="+potfile)
+	}
+
+	if hasUsername {
+		args = append(args, "--username")
+	}
+
+	job := newJobState()
+	job.hook = hook
+
+	_, err := hc.run(append(args, hashfile), job)
+	return err
+}
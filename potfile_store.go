@@ -0,0 +1,300 @@
+package gocat
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/niall-san/gocat/v7/potfile"
+)
+
+// ErrPotfileStoreWithoutHashes is raised by Run when Options.PotfileStore is set but the job's hashes aren't in Job.Hashes
+var ErrPotfileStoreWithoutHashes = errors.New("gocat: Options.PotfileStore can only be used with Job.Hashes")
+
+// PotfileStore stores cracked hashes. When Options.PotfileStore is set, sessions started by Run use the store instead of
+// hashcat's potfile: the entries of Job.Hashes that are already in the store are reported as potfile hits without being
+// cracked again and every hash cracked during the session is added to the store.
+// Hashfiles can't be checked against the store, so Run returns ErrPotfileStoreWithoutHashes for jobs that don't use Job.Hashes.
+// Implementations must be safe for concurrent use. Stores that buffer writes can implement Flush() error,
+// it's called once the session has ended
+type PotfileStore interface {
+	// Lookup returns the plaintext of hash and true if hash has been cracked
+	Lookup(hash string) (plain string, ok bool, err error)
+	// Add stores the plaintext of a cracked hash
+	Add(hash, plain string) error
+	// Iterate calls fn for every cracked hash in the store until fn returns false
+	Iterate(fn func(hash, plain string) bool) error
+}
+
+// MemoryPotfileStore is a PotfileStore that keeps every cracked hash in memory
+type MemoryPotfileStore struct {
+	mu      sync.RWMutex
+	cracked map[string]string
+}
+
+// NewMemoryPotfileStore returns an empty MemoryPotfileStore
+func NewMemoryPotfileStore() *MemoryPotfileStore {
+	return &MemoryPotfileStore{
+		cracked: make(map[string]string),
+	}
+}
+
+// Lookup implements PotfileStore
+func (s *MemoryPotfileStore) Lookup(hash string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plain, ok := s.cracked[hash]
+	return plain, ok, nil
+}
+
+// Add implements PotfileStore
+func (s *MemoryPotfileStore) Add(hash, plain string) error {
+	s.mu.Lock()
+	s.cracked[hash] = plain
+	s.mu.Unlock()
+	return nil
+}
+
+// Iterate implements PotfileStore
+func (s *MemoryPotfileStore) Iterate(fn func(hash, plain string) bool) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for hash, plain := range s.cracked {
+		if !fn(hash, plain) {
+			break
+		}
+	}
+	return nil
+}
+
+// potfileStoreFlusher is implemented by stores that buffer writes
+type potfileStoreFlusher interface {
+	Flush() error
+}
+
+// FilePotfileStore is a PotfileStore backed by a potfile in hashcat's format (see the potfile package).
+// The file is read into memory the first time the store is used and new cracks are appended to it through a buffered
+// writer that's kept open, call Flush or Close to write them out (Run flushes the store once a session has ended).
+// Adding a hash with the plaintext it's already stored with is a no-op
+type FilePotfileStore struct {
+	path string
+
+	once    sync.Once
+	loadErr error
+	mem     *MemoryPotfileStore
+
+	mu sync.Mutex
+	f  *os.File
+	w  *potfile.Writer
+}
+
+// NewFilePotfileStore returns a FilePotfileStore for the potfile at path. The file is created when the first hash is added
+func NewFilePotfileStore(path string) *FilePotfileStore {
+	return &FilePotfileStore{
+		path: path,
+		mem:  NewMemoryPotfileStore(),
+	}
+}
+
+func (s *FilePotfileStore) load() error {
+	s.once.Do(func() {
+		f, err := os.Open(s.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		} else if err != nil {
+			s.loadErr = err
+			return
+		}
+		defer f.Close()
+
+		r := potfile.NewReader(f, potfile.Options{})
+		for {
+			entry, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+
+			var pe *potfile.ParseError
+			if errors.As(err, &pe) {
+				// hashcat ignores lines it can't parse too
+				continue
+			} else if err != nil {
+				s.loadErr = err
+				return
+			}
+
+			s.mem.Add(entry.Hash, entry.Plain)
+		}
+	})
+	return s.loadErr
+}
+
+// Lookup implements PotfileStore
+func (s *FilePotfileStore) Lookup(hash string) (string, bool, error) {
+	if err := s.load(); err != nil {
+		return "", false, err
+	}
+	return s.mem.Lookup(hash)
+}
+
+// Add implements PotfileStore
+func (s *FilePotfileStore) Add(hash, plain string) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok, _ := s.mem.Lookup(hash); ok && existing == plain {
+		return nil
+	}
+
+	if s.w == nil {
+		f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		s.f, s.w = f, potfile.NewWriter(f, potfile.Options{})
+	}
+
+	if err := s.w.Write(potfile.Entry{Hash: hash, Plain: plain}); err != nil {
+		return err
+	}
+	return s.mem.Add(hash, plain)
+}
+
+// Flush writes the buffered cracks to the potfile
+func (s *FilePotfileStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w == nil {
+		return nil
+	}
+	return s.w.Flush()
+}
+
+// Close flushes the buffered cracks and closes the potfile. The store can still be used, it's reopened by the next Add
+func (s *FilePotfileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+
+	err := s.w.Flush()
+	if closeErr := s.f.Close(); err == nil {
+		err = closeErr
+	}
+	s.f, s.w = nil, nil
+	return err
+}
+
+// Iterate implements PotfileStore
+func (s *FilePotfileStore) Iterate(fn func(hash, plain string) bool) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	return s.mem.Iterate(fn)
+}
+
+// ShardedPotfileStore is a PotfileStore that spreads cracked hashes over a directory of potfiles.
+// A shard is only read from disk once a hash that belongs to it is looked up or added
+type ShardedPotfileStore struct {
+	shards []*FilePotfileStore
+}
+
+// NewShardedPotfileStore returns a ShardedPotfileStore that keeps numShards potfiles in dir. dir is created if it doesn't exist.
+// The same number of shards must be used every time dir is opened
+func NewShardedPotfileStore(dir string, numShards int) (*ShardedPotfileStore, error) {
+	if numShards <= 0 {
+		return nil, fmt.Errorf("invalid number of shards: %d", numShards)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	s := &ShardedPotfileStore{
+		shards: make([]*FilePotfileStore, numShards),
+	}
+	for i := range s.shards {
+		s.shards[i] = NewFilePotfileStore(filepath.Join(dir, fmt.Sprintf("%04d.potfile", i)))
+	}
+	return s, nil
+}
+
+func (s *ShardedPotfileStore) shard(hash string) *FilePotfileStore {
+	h := fnv.New32a()
+	h.Write([]byte(hash))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Lookup implements PotfileStore
+func (s *ShardedPotfileStore) Lookup(hash string) (string, bool, error) {
+	return s.shard(hash).Lookup(hash)
+}
+
+// Add implements PotfileStore
+func (s *ShardedPotfileStore) Add(hash, plain string) error {
+	return s.shard(hash).Add(hash, plain)
+}
+
+// Flush writes the buffered cracks of every shard to disk
+func (s *ShardedPotfileStore) Flush() error {
+	var errs []error
+	for _, shard := range s.shards {
+		errs = append(errs, shard.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every shard, see FilePotfileStore.Close
+func (s *ShardedPotfileStore) Close() error {
+	var errs []error
+	for _, shard := range s.shards {
+		errs = append(errs, shard.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Iterate implements PotfileStore
+func (s *ShardedPotfileStore) Iterate(fn func(hash, plain string) bool) error {
+	keepGoing := true
+	for _, shard := range s.shards {
+		err := shard.Iterate(func(hash, plain string) bool {
+			keepGoing = fn(hash, plain)
+			return keepGoing
+		})
+		if err != nil || !keepGoing {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupPotfileStore looks up an entry of Job.Hashes in store. hashcat reports hashes in its normalized form
+// (most commonly lowercase hex) so that is tried as well
+func lookupPotfileStore(store PotfileStore, entry, sep string, usernames bool) (hash, plain string, ok bool, err error) {
+	hash = entry
+	if usernames {
+		_, hash = splitUsername(entry, sep)
+	}
+
+	for _, candidate := range []string{hash, strings.ToLower(hash)} {
+		if plain, ok, err = store.Lookup(candidate); err != nil || ok {
+			return candidate, plain, ok, err
+		}
+	}
+	return hash, "", false, nil
+}
@@ -0,0 +1,123 @@
+package gocat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/niall-san/gocat/v7/hcargp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPotfileStore(t *testing.T, store PotfileStore) {
+	_, ok, err := store.Lookup("5d41402abc4b2a76b9719d911017c592")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Add("5d41402abc4b2a76b9719d911017c592", "hello"))
+	require.NoError(t, store.Add("7d793037a0760186574b0282f2f435e7", "wor:ld"))
+
+	plain, ok, err := store.Lookup("5d41402abc4b2a76b9719d911017c592")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "hello", plain)
+
+	all := map[string]string{}
+	require.NoError(t, store.Iterate(func(hash, plain string) bool {
+		all[hash] = plain
+		return true
+	}))
+	assert.Equal(t, map[string]string{
+		"5d41402abc4b2a76b9719d911017c592": "hello",
+		"7d793037a0760186574b0282f2f435e7": "wor:ld",
+	}, all)
+
+	calls := 0
+	require.NoError(t, store.Iterate(func(hash, plain string) bool {
+		calls++
+		return false
+	}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestMemoryPotfileStore(t *testing.T) {
+	testPotfileStore(t, NewMemoryPotfileStore())
+}
+
+func TestFilePotfileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.potfile")
+	store := NewFilePotfileStore(path)
+	testPotfileStore(t, store)
+
+	// cracks are buffered until the store is flushed and the same crack is only written once
+	require.NoError(t, store.Add("5d41402abc4b2a76b9719d911017c592", "hello"))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, b)
+
+	require.NoError(t, store.Close())
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592:hello\n7d793037a0760186574b0282f2f435e7:$HEX[776f723a6c64]\n", string(b))
+
+	// reopening the potfile must see the existing entries
+	plain, ok, err := NewFilePotfileStore(path).Lookup("7d793037a0760186574b0282f2f435e7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "wor:ld", plain)
+
+	plain, ok, err = NewFilePotfileStore("./testdata/two_md5.potfile").Lookup("5d41402abc4b2a76b9719d911017c592")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "hello", plain)
+}
+
+func TestShardedPotfileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewShardedPotfileStore(dir, 4)
+	require.NoError(t, err)
+	testPotfileStore(t, store)
+	require.NoError(t, store.Flush())
+
+	store, err = NewShardedPotfileStore(dir, 4)
+	require.NoError(t, err)
+	plain, ok, err := store.Lookup("7d793037a0760186574b0282f2f435e7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "wor:ld", plain)
+
+	_, err = NewShardedPotfileStore(dir, 0)
+	assert.Error(t, err)
+}
+
+func TestLookupPotfileStore(t *testing.T) {
+	store := NewMemoryPotfileStore()
+	store.Add("5d41402abc4b2a76b9719d911017c592", "hello")
+
+	hash, plain, ok, err := lookupPotfileStore(store, "bob:5D41402ABC4B2A76B9719D911017C592", ":", true)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", hash)
+	assert.Equal(t, "hello", plain)
+
+	_, _, ok, err = lookupPotfileStore(store, "7d793037a0760186574b0282f2f435e7", ":", false)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUsePotfileStoreRequiresHashes(t *testing.T) {
+	hc := &Hashcat{}
+	store := NewMemoryPotfileStore()
+
+	for _, job := range []Job{
+		{Args: []string{"-m", "0", "hashes.txt", "words.txt"}},
+		{Options: &hcargp.HashcatSessionOptions{InputFile: "hashes.txt"}},
+	} {
+		state := newJobState()
+		_, _, err := hc.usePotfileStore(store, job, state)
+		require.True(t, errors.Is(err, ErrPotfileStoreWithoutHashes))
+		assert.False(t, state.potfileStore)
+	}
+}
@@ -0,0 +1,98 @@
+package gocat
+
+import (
+	"sync"
+	"time"
+)
+
+// RecoveryRate contains how many hashes were cracked over the last minute, hour, and day.
+// This is the equivalent of "Recovered/Time" in hashcat's status screen
+type RecoveryRate struct {
+	PerMinute int
+	PerHour   int
+	PerDay    int
+}
+
+// RecoveryThreshold stops a session that isn't cracking enough hashes.
+// Once a session has been cracking for Window, it's stopped if fewer than MinCracks hashes were cracked over the last Window.
+// A paused session is never stopped and the Window starts over once it's resumed
+type RecoveryThreshold struct {
+	Window    time.Duration
+	MinCracks int
+}
+
+func (t RecoveryThreshold) enabled() bool {
+	return t.Window > 0 && t.MinCracks > 0
+}
+
+// checkInterval is how often the threshold is checked against the recovery history
+func (t RecoveryThreshold) checkInterval() time.Duration {
+	interval := t.Window / 60
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return interval
+}
+
+// crackBucket counts the cracks that happened within the same second
+type crackBucket struct {
+	at int64
+	n  int
+}
+
+// recoveryHistory keeps track of when hashes were cracked. Cracks are bucketed per second so memory stays
+// bounded by keep rather than by the number of cracked hashes
+type recoveryHistory struct {
+	mu      sync.Mutex
+	keep    time.Duration
+	buckets []crackBucket
+}
+
+func newRecoveryHistory(keep time.Duration) *recoveryHistory {
+	if keep < 24*time.Hour {
+		keep = 24 * time.Hour
+	}
+	return &recoveryHistory{keep: keep}
+}
+
+func (h *recoveryHistory) add(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sec := now.Unix()
+	if n := len(h.buckets); n > 0 && h.buckets[n-1].at >= sec {
+		h.buckets[n-1].n++
+	} else {
+		h.buckets = append(h.buckets, crackBucket{at: sec, n: 1})
+	}
+
+	cutoff := now.Add(-h.keep).Unix()
+	i := 0
+	for i < len(h.buckets) && h.buckets[i].at <= cutoff {
+		i++
+	}
+	if i > 0 {
+		h.buckets = append(h.buckets[:0], h.buckets[i:]...)
+	}
+}
+
+// count returns the number of cracks within window of now
+func (h *recoveryHistory) count(now time.Time, window time.Duration) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := now.Add(-window).Unix()
+	total := 0
+	for i := len(h.buckets) - 1; i >= 0 && h.buckets[i].at > cutoff; i-- {
+		total += h.buckets[i].n
+	}
+	return total
+}
+
+func (h *recoveryHistory) rate(now time.Time) RecoveryRate {
+	return RecoveryRate{
+		PerMinute: h.count(now, time.Minute),
+		PerHour:   h.count(now, time.Hour),
+		PerDay:    h.count(now, 24*time.Hour),
+	}
+}
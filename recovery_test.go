@@ -0,0 +1,79 @@
+package gocat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryHistory(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := newRecoveryHistory(0)
+
+	h.add(now.Add(-25 * time.Hour))
+	h.add(now.Add(-2 * time.Hour))
+	h.add(now.Add(-30 * time.Minute))
+	h.add(now.Add(-30 * time.Second))
+	h.add(now.Add(-30 * time.Second))
+	h.add(now)
+
+	assert.Equal(t, RecoveryRate{PerMinute: 3, PerHour: 4, PerDay: 5}, h.rate(now))
+	assert.Equal(t, 4, h.count(now, time.Hour))
+	// the crack from 25 hours ago was pruned and the two cracks 30 seconds ago share a bucket
+	assert.Len(t, h.buckets, 4)
+}
+
+func TestRecoveryThresholdCheckInterval(t *testing.T) {
+	assert.Equal(t, time.Second, RecoveryThreshold{Window: 10 * time.Second}.checkInterval())
+	assert.Equal(t, time.Minute, RecoveryThreshold{Window: time.Hour}.checkInterval())
+	assert.False(t, RecoveryThreshold{Window: time.Hour}.enabled())
+	assert.True(t, RecoveryThreshold{Window: time.Hour, MinCracks: 1}.enabled())
+}
+
+func TestJobStateRecoveryStalled(t *testing.T) {
+	threshold := RecoveryThreshold{Window: time.Minute, MinCracks: 2}
+	job := newJobState()
+	job.history = newRecoveryHistory(threshold.Window)
+
+	now := time.Now()
+	_, stalled := job.recoveryStalled(threshold, now)
+	assert.False(t, stalled, "cracking hasn't started yet")
+
+	job.crackingStartedAt = now.Add(-30 * time.Second)
+	_, stalled = job.recoveryStalled(threshold, now)
+	assert.False(t, stalled, "the window hasn't elapsed yet")
+
+	job.crackingStartedAt = now.Add(-2 * time.Minute)
+	job.history.add(now.Add(-90 * time.Second))
+	job.history.add(now.Add(-10 * time.Second))
+	job.history.add(now.Add(-5 * time.Second))
+	cracks, stalled := job.recoveryStalled(threshold, now)
+	assert.False(t, stalled)
+	assert.Equal(t, 2, cracks)
+
+	cracks, stalled = job.recoveryStalled(threshold, now.Add(52*time.Second))
+	assert.True(t, stalled)
+	assert.Equal(t, 1, cracks)
+
+	// resuming a paused session starts the window over
+	job.restartRecoveryWindow()
+	_, stalled = job.recoveryStalled(threshold, time.Now().Add(30*time.Second))
+	assert.False(t, stalled)
+}
+
+func TestCheckRecoveryThresholdWhilePaused(t *testing.T) {
+	threshold := RecoveryThreshold{Window: time.Minute, MinCracks: 1}
+	hc := &Hashcat{opts: Options{RecoveryThreshold: threshold}}
+
+	job := newJobState()
+	job.history = newRecoveryHistory(threshold.Window)
+	job.crackingStartedAt = time.Now().Add(-time.Hour)
+	hc.job.Store(job)
+
+	for _, state := range []State{StatePaused, StateAutotuning, StateCheckpointing} {
+		hc.state.Store(int32(state))
+		assert.False(t, hc.checkRecoveryThreshold(), state.String())
+		assert.False(t, job.result.RecoveryStalled, state.String())
+	}
+}
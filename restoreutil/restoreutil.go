@@ -30,7 +30,8 @@ type RestoreData struct {
 	ArgCount uint32
 
 	ArgvPointer uint64
-	// Args contains the command line arguments
+	// Args contains the command line arguments, starting with the executable that ran the session.
+	// hcargp.UnmarshalOptions{SkipProgram: true} parses them back into options
 	Args []string
 }
 
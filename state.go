@@ -0,0 +1,106 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"fmt"
+	"time"
+)
+
+// State is the lifecycle state of a Hashcat instance
+type State int32
+
+const (
+	// StateIdle indicates no session has been run yet
+	StateIdle State = iota
+	// StateInitializing indicates a session is parsing options, loading hashes and initializing devices
+	StateInitializing
+	// StateAutotuning indicates hashcat is autotuning the devices
+	StateAutotuning
+	// StateRunning indicates the session is cracking
+	StateRunning
+	// StatePaused indicates the session was suspended with Pause
+	StatePaused
+	// StateCheckpointing indicates the session will stop at the next checkpoint
+	StateCheckpointing
+	// StateFinished indicates the last session has ended
+	StateFinished
+)
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "Idle"
+	case StateInitializing:
+		return "Initializing"
+	case StateAutotuning:
+		return "Autotuning"
+	case StateRunning:
+		return "Running"
+	case StatePaused:
+		return "Paused"
+	case StateCheckpointing:
+		return "Checkpointing"
+	case StateFinished:
+		return "Finished"
+	default:
+		return "Unknown"
+	}
+}
+
+// StateChangePayload is sent to the user via the callback whenever the state of the Hashcat instance changes
+type StateChangePayload struct {
+	From      State
+	To        State
+	ChangedAt time.Time
+}
+
+// StateError is returned by a control method (Pause, Resume, StopAtCheckpoint, etc) called while the session
+// is in a state that doesn't allow it. Err is the error describing why the call failed
+type StateError struct {
+	Op    string
+	State State
+	Err   error
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("gocat: cannot %s while session is %s", e.Op, e.State)
+}
+
+func (e *StateError) Unwrap() error {
+	return e.Err
+}
+
+// State returns the current lifecycle state of the Hashcat instance
+func (hc *Hashcat) State() State {
+	return State(hc.state.Load())
+}
+
+// setState moves the instance into state to and notifies the user if it changed.
+// id is the hashcat event that caused the change or 0 if it wasn't caused by hashcat
+func (hc *Hashcat) setState(id uint32, to State) {
+	from := State(hc.state.Swap(int32(to)))
+	if from == to {
+		return
+	}
+
+	hc.emit(&hc.wrapper.ctx, id, StateChangePayload{
+		From:      from,
+		To:        to,
+		ChangedAt: time.Now().UTC(),
+	})
+}
+
+// stateFromEvent returns the state hashcat moves into when it fires event id.
+// EVENT_OUTERLOOP_FINISHED fires after every mask or dictionary so only run moves the instance into StateFinished
+func stateFromEvent(id uint32) (State, bool) {
+	switch id {
+	case C.EVENT_BACKEND_SESSION_PRE:
+		return StateInitializing, true
+	case C.EVENT_AUTOTUNE_STARTING:
+		return StateAutotuning, true
+	case C.EVENT_AUTOTUNE_FINISHED, C.EVENT_CRACKER_STARTING:
+		return StateRunning, true
+	}
+	return StateIdle, false
+}
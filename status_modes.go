@@ -0,0 +1,115 @@
+package gocat
+
+// #include "wrapper.h"
+import "C"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GuessMode describes where hashcat's candidates are coming from, see Status.GuessMode
+type GuessMode int
+
+// Guess modes reported by hashcat
+const (
+	GuessModeNone                   GuessMode = C.GUESS_MODE_NONE
+	GuessModeStraightFile           GuessMode = C.GUESS_MODE_STRAIGHT_FILE
+	GuessModeStraightFileRulesFile  GuessMode = C.GUESS_MODE_STRAIGHT_FILE_RULES_FILE
+	GuessModeStraightFileRulesGen   GuessMode = C.GUESS_MODE_STRAIGHT_FILE_RULES_GEN
+	GuessModeStraightStdin          GuessMode = C.GUESS_MODE_STRAIGHT_STDIN
+	GuessModeStraightStdinRulesFile GuessMode = C.GUESS_MODE_STRAIGHT_STDIN_RULES_FILE
+	GuessModeStraightStdinRulesGen  GuessMode = C.GUESS_MODE_STRAIGHT_STDIN_RULES_GEN
+	GuessModeCombinatorBaseLeft     GuessMode = C.GUESS_MODE_COMBINATOR_BASE_LEFT
+	GuessModeCombinatorBaseRight    GuessMode = C.GUESS_MODE_COMBINATOR_BASE_RIGHT
+	GuessModeMask                   GuessMode = C.GUESS_MODE_MASK
+	GuessModeMaskCS                 GuessMode = C.GUESS_MODE_MASK_CS
+	GuessModeHybrid1                GuessMode = C.GUESS_MODE_HYBRID1
+	GuessModeHybrid1CS              GuessMode = C.GUESS_MODE_HYBRID1_CS
+	GuessModeHybrid2                GuessMode = C.GUESS_MODE_HYBRID2
+	GuessModeHybrid2CS              GuessMode = C.GUESS_MODE_HYBRID2_CS
+)
+
+var guessModeNames = map[GuessMode]string{
+	GuessModeNone:                   "None",
+	GuessModeStraightFile:           "Wordlist",
+	GuessModeStraightFileRulesFile:  "Wordlist + Rules",
+	GuessModeStraightFileRulesGen:   "Wordlist + Generated Rules",
+	GuessModeStraightStdin:          "Pipe",
+	GuessModeStraightStdinRulesFile: "Pipe + Rules",
+	GuessModeStraightStdinRulesGen:  "Pipe + Generated Rules",
+	GuessModeCombinatorBaseLeft:     "Combinator, Left Side",
+	GuessModeCombinatorBaseRight:    "Combinator, Right Side",
+	GuessModeMask:                   "Mask",
+	GuessModeMaskCS:                 "Mask + Custom Charset",
+	GuessModeHybrid1:                "Hybrid Wordlist + Mask",
+	GuessModeHybrid1CS:              "Hybrid Wordlist + Mask, Custom Charset",
+	GuessModeHybrid2:                "Hybrid Mask + Wordlist",
+	GuessModeHybrid2CS:              "Hybrid Mask + Wordlist, Custom Charset",
+}
+
+func (m GuessMode) String() string {
+	if name, ok := guessModeNames[m]; ok {
+		return name
+	}
+	return "GuessMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseGuessMode parses a guess mode from its number ("9") or name ("Mask"), the name is case insensitive
+func ParseGuessMode(s string) (GuessMode, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := guessModeNames[GuessMode(n)]; ok {
+			return GuessMode(n), nil
+		}
+		return 0, fmt.Errorf("invalid guess mode %d", n)
+	}
+
+	for m, name := range guessModeNames {
+		if strings.EqualFold(name, s) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid guess mode %q", s)
+}
+
+// ProgressMode describes whether hashcat knows the size of the keyspace, see Status.ProgressMode
+type ProgressMode int
+
+// Progress modes reported by hashcat
+const (
+	ProgressModeNone            ProgressMode = C.PROGRESS_MODE_NONE
+	ProgressModeKeyspaceKnown   ProgressMode = C.PROGRESS_MODE_KEYSPACE_KNOWN
+	ProgressModeKeyspaceUnknown ProgressMode = C.PROGRESS_MODE_KEYSPACE_UNKNOWN
+)
+
+var progressModeNames = map[ProgressMode]string{
+	ProgressModeNone:            "None",
+	ProgressModeKeyspaceKnown:   "Keyspace Known",
+	ProgressModeKeyspaceUnknown: "Keyspace Unknown",
+}
+
+func (m ProgressMode) String() string {
+	if name, ok := progressModeNames[m]; ok {
+		return name
+	}
+	return "ProgressMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseProgressMode parses a progress mode from its number ("1") or name ("Keyspace Known"), the name is case insensitive
+func ParseProgressMode(s string) (ProgressMode, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := progressModeNames[ProgressMode(n)]; ok {
+			return ProgressMode(n), nil
+		}
+		return 0, fmt.Errorf("invalid progress mode %d", n)
+	}
+
+	for m, name := range progressModeNames {
+		if strings.EqualFold(name, s) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid progress mode %q", s)
+}